@@ -1,3 +0,0 @@
-module advent2022/16/1
-
-go 1.19
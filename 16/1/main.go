@@ -0,0 +1,11 @@
+package main
+
+import (
+	"advent2022/16/cli"
+	"advent2022/16/valves"
+)
+
+func main() {
+	// Just me, with 30 minutes.
+	cli.Run(valves.PartOneConfig)
+}
@@ -0,0 +1,12 @@
+package main
+
+import (
+	"advent2022/16/cli"
+	"advent2022/16/valves"
+)
+
+func main() {
+	// Me and the elephant, with 26 minutes each.
+	cli.Run(valves.PartTwoConfig)
+	// 2063 is too low (?)
+}
@@ -0,0 +1,158 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"runtime"
+	"time"
+
+	"advent2022/16/valves"
+)
+
+// Options for how to run the solver, on top of the config for the puzzle.
+type options struct {
+	useDP      bool
+	boundName  string
+	workers    int
+	jsonOutput bool
+	// Where to write the graph and route for Graphviz, if anywhere.
+	dotFile string
+	dotRaw  bool
+	// Limits for stopping the search early. Zero means no limit.
+	timeout     time.Duration
+	maxExpanded int
+}
+
+// Runs an anytime search, printing each better schedule as it's found.
+func solveAnytime(graph *valves.Graph, config valves.Config, bound valves.Bound, opts options) (valves.Schedule, valves.SearchStats) {
+	ctx := context.Background()
+	if opts.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.timeout)
+		defer cancel()
+	}
+
+	improvements := make(chan valves.Schedule)
+	done := make(chan struct{})
+	go func() {
+		for s := range improvements {
+			if !opts.jsonOutput {
+				fmt.Println("Found new best", s.TotalPressure)
+			}
+		}
+		close(done)
+	}()
+
+	result := valves.SolveAnytime(ctx, graph, config, valves.AnytimeOptions{
+		Bound:        bound,
+		MaxExpanded:  opts.maxExpanded,
+		Improvements: improvements,
+	})
+	<-done
+
+	if !opts.jsonOutput && !result.Complete {
+		fmt.Println("Stopped early, the best possible is at most", result.UpperBound, "\tgap", result.Gap())
+	}
+	return result.Best, result.Stats
+}
+
+func solve(filename string, config valves.Config, opts options) error {
+	graph, err := valves.GraphFromFile(filename, config)
+	if err != nil {
+		return err
+	}
+	if err := config.Validate(graph); err != nil {
+		return fmt.Errorf("%s: %v", filename, err)
+	}
+
+	var schedule valves.Schedule
+	var stats valves.SearchStats
+	if opts.useDP {
+		schedule = valves.SolveDP(graph, config)
+	} else {
+		var bound valves.Bound
+		switch opts.boundName {
+		case "naive":
+			bound = valves.NaiveBound{}
+		case "greedy":
+			bound = valves.NewGreedyBound(graph)
+		default:
+			return fmt.Errorf("unknown bound %s, use naive or greedy", opts.boundName)
+		}
+		if opts.timeout > 0 || opts.maxExpanded > 0 {
+			schedule, stats = solveAnytime(graph, config, bound, opts)
+		} else {
+			schedule, stats = valves.SolveParallel(graph, config, bound, opts.workers)
+		}
+	}
+
+	if opts.dotFile != "" {
+		dotGraph := graph
+		if opts.dotRaw {
+			dotGraph, err = valves.ReadGraphFile(filename)
+			if err != nil {
+				return err
+			}
+		}
+		dotOpts := valves.DotOptions{
+			StartNode: config.StartNode,
+			Schedule:  &schedule,
+		}
+		if err := dotGraph.OutputAsDotFile(opts.dotFile, dotOpts); err != nil {
+			return err
+		}
+	}
+
+	if opts.jsonOutput {
+		out, err := json.MarshalIndent(schedule, "", "  ")
+		if err != nil {
+			panic(err)
+		}
+		fmt.Println(string(out))
+		return nil
+	}
+	fmt.Println("Solved", filename)
+	if !opts.useDP {
+		fmt.Println("Searched", stats.Searched, "skipped", stats.Skipped)
+	}
+	fmt.Println(schedule)
+	return nil
+}
+
+// Runs the solver on the input file given on the command line. Each field of
+// the config can be overridden with a flag.
+func Run(defaults valves.Config) {
+	config := defaults
+	flag.StringVar(&config.StartNode, "start", defaults.StartNode, "node every agent starts from")
+	flag.IntVar(&config.AgentMinutes, "minutes", defaults.AgentMinutes, "minutes each agent has")
+	flag.IntVar(&config.OpenTime, "open-time", defaults.OpenTime, "minutes it takes to open a valve")
+	flag.IntVar(&config.NumAgents, "agents", defaults.NumAgents, "number of agents working together")
+
+	opts := options{}
+	flag.BoolVar(&opts.useDP, "dp", false, "find the best flow for each set of valves instead of using branch and bound")
+	flag.StringVar(&opts.boundName, "bound", "greedy", "upper bound for branch and bound: naive or greedy")
+	flag.IntVar(&opts.workers, "workers", runtime.NumCPU(), "number of goroutines to split the branch and bound search between")
+	flag.BoolVar(&opts.jsonOutput, "json", false, "print the schedule as JSON")
+	flag.DurationVar(&opts.timeout, "timeout", 0, "stop the branch and bound search after this long, and print the best so far")
+	flag.IntVar(&opts.maxExpanded, "max-expanded", 0, "stop the branch and bound search after expanding this many states")
+	flag.StringVar(&opts.dotFile, "dot", "", "write the graph and the route taken to this Graphviz file")
+	flag.BoolVar(&opts.dotRaw, "dot-raw", false, "draw the graph as it's parsed, instead of with the zero flow valves removed")
+
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [flags] <input file>\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(1)
+	}
+
+	if err := solve(flag.Arg(0), config, opts); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,7 @@
+module advent2022/16
+
+go 1.19
+
+require advent2022/common v0.0.0
+
+replace advent2022/common => ../common
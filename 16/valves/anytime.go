@@ -0,0 +1,112 @@
+package valves
+
+import (
+	"context"
+)
+
+// How many states to expand between checks of whether the context is done.
+const contextCheckInterval = 256
+
+// Options for SolveAnytime. The zero value searches until it's done.
+type AnytimeOptions struct {
+	// Used to prune the search. If nil, the greedy bound is used.
+	Bound Bound
+	// Stop after expanding this many states. 0 means no limit.
+	MaxExpanded int
+	// If set, each schedule that beats the best so far is sent here as soon
+	// as it's found. The channel is closed when the search ends.
+	Improvements chan<- Schedule
+}
+
+// The result of a search that might have been stopped early.
+type AnytimeResult struct {
+	// The best schedule found so far.
+	Best Schedule
+	// No schedule can release more pressure than this. If the search
+	// finished, it's the same as the best schedule's pressure.
+	UpperBound int
+	// Whether the whole search space was covered, so Best is optimal.
+	Complete bool
+	Stats    SearchStats
+}
+
+// How much better than the best schedule found the optimal schedule could
+// be.
+func (r AnytimeResult) Gap() int {
+	return r.UpperBound - r.Best.TotalPressure
+}
+
+// Does a depth first branch and bound search, which can be stopped at any time
+// by cancelling the context or by running out of states to expand. It always
+// returns the best schedule found so far, along with an upper bound on the
+// best possible schedule from the parts of the search that were left.
+func SolveAnytime(ctx context.Context, g *Graph, config Config, opts AnytimeOptions) AnytimeResult {
+	if opts.Improvements != nil {
+		defer close(opts.Improvements)
+	}
+	if config.NumAgents < 1 {
+		return AnytimeResult{Complete: true}
+	}
+
+	config.check(g)
+
+	bound := opts.Bound
+	if bound == nil {
+		bound = NewGreedyBound(g)
+	}
+
+	s := initialState(g, config)
+	best := s
+	stats := SearchStats{}
+
+	toVisit := []SearchState{s}
+	stopped := false
+	for i := 0; len(toVisit) > 0; i++ {
+		if opts.MaxExpanded > 0 && stats.Searched >= opts.MaxExpanded {
+			stopped = true
+			break
+		}
+		if i%contextCheckInterval == 0 && ctx.Err() != nil {
+			stopped = true
+			break
+		}
+
+		// Pop the last node
+		currentState := toVisit[len(toVisit)-1]
+		toVisit = toVisit[:len(toVisit)-1]
+
+		if currentState.Flow > best.Flow {
+			best = currentState
+			if opts.Improvements != nil {
+				select {
+				case opts.Improvements <- ScheduleFromState(best, config.NumAgents):
+				case <-ctx.Done():
+				}
+			}
+		}
+
+		// Bound: Don't explore substates if the upper bound is less than the best
+		if bound.UpperBound(g, currentState, config) < best.Flow {
+			stats.Skipped++
+			continue
+		}
+
+		// Add the substates to the list of states to visit
+		toVisit = append(toVisit, currentState.GetSubStates(g, config)...)
+		stats.Searched++
+	}
+
+	// Everything that's been pruned is below the best, so the only way to do
+	// better is through the states that are left.
+	upperBound := best.Flow
+	for _, s := range toVisit {
+		upperBound = intMax(upperBound, bound.UpperBound(g, s, config))
+	}
+
+	return AnytimeResult{
+		Best:       ScheduleFromState(best, config.NumAgents),
+		UpperBound: upperBound,
+		Complete:   !stopped,
+		Stats:      stats,
+	}
+}
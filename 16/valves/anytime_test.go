@@ -0,0 +1,59 @@
+package valves
+
+import (
+	"context"
+	"testing"
+)
+
+func TestSolveAnytimeComplete(t *testing.T) {
+	g := exampleGraph(t, PartTwoConfig)
+	improvements := make(chan Schedule)
+	found := make([]int, 0)
+	done := make(chan struct{})
+	go func() {
+		for s := range improvements {
+			found = append(found, s.TotalPressure)
+		}
+		close(done)
+	}()
+
+	result := SolveAnytime(context.Background(), g, PartTwoConfig, AnytimeOptions{Improvements: improvements})
+	<-done
+
+	if !result.Complete || result.Best.TotalPressure != 1707 || result.Gap() != 0 {
+		t.Errorf("Expected a complete search with 1707 and no gap, got %+v", result)
+	}
+	for i := 1; i < len(found); i++ {
+		if found[i] <= found[i-1] {
+			t.Errorf("Expected each improvement to be better, got %v", found)
+		}
+	}
+	if len(found) == 0 || found[len(found)-1] != 1707 {
+		t.Errorf("Expected the last improvement to be 1707, got %v", found)
+	}
+}
+
+func TestSolveAnytimeStopsEarly(t *testing.T) {
+	g := exampleGraph(t, PartTwoConfig)
+
+	result := SolveAnytime(context.Background(), g, PartTwoConfig, AnytimeOptions{MaxExpanded: 5})
+	if result.Complete {
+		t.Errorf("Expected the search to stop early")
+	}
+	if result.Stats.Searched != 5 {
+		t.Errorf("Expected 5 states to be searched, got %d", result.Stats.Searched)
+	}
+	if result.UpperBound < 1707 || result.Best.TotalPressure > 1707 {
+		t.Errorf("Expected the best and upper bound to be either side of 1707, got %+v", result)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	result = SolveAnytime(ctx, g, PartTwoConfig, AnytimeOptions{})
+	if result.Complete || result.Stats.Searched != 0 {
+		t.Errorf("Expected a cancelled search to stop straight away, got %+v", result)
+	}
+	if result.UpperBound < 1707 {
+		t.Errorf("Expected the upper bound to be at least 1707, got %d", result.UpperBound)
+	}
+}
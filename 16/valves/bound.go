@@ -0,0 +1,119 @@
+package valves
+
+import (
+	"sort"
+)
+
+// Works out an upper bound on the total flow that can be reached from a
+// search state, for pruning the search.
+type Bound interface {
+	UpperBound(g *Graph, s SearchState, config Config) int
+}
+
+// Pretends every agent can get to every valve directly, so each valve is
+// counted as if it were the first one the agent opened.
+type NaiveBound struct{}
+
+func (NaiveBound) UpperBound(g *Graph, s SearchState, config Config) int {
+	value := UpperBoundFromNode(s.CurrNode, g, s.Visited, s.TimeLeft)
+	// The upper bound also includes the value of each agent that hasn't
+	// started yet doing its own search from the start node.
+	value += s.AgentsLeft * UpperBoundFromNode(g.Indices[config.StartNode], g, s.Visited, config.AgentMinutes)
+	return s.Flow + value
+}
+
+// The flow from visiting all the nodes that are reachable within the time left from this node
+func UpperBoundFromNode(node int, g *Graph, alreadyVisited ValveSet, timeLeft int) int {
+	extraFlow := 0
+	for other, value := range g.Values {
+		if alreadyVisited.Contains(other) {
+			continue
+		}
+		dist := g.Distances.Get(node, other)
+		if dist > timeLeft {
+			continue
+		}
+		extraFlow += value * (timeLeft - dist)
+	}
+	return extraFlow
+}
+
+// Opens the valves in descending order of value, pretending that every valve
+// after the first is only the shortest distance between any two valves away.
+// Every real schedule opens its valves no earlier than this, so giving the
+// most valuable valves the most time is an upper bound.
+type GreedyBound struct {
+	// Indices of the valves worth opening, most valuable first.
+	ByValue []int
+	// The shortest distance between any two valves worth opening.
+	MinDistance int
+}
+
+func NewGreedyBound(g *Graph) *GreedyBound {
+	byValue := make([]int, 0)
+	for node, value := range g.Values {
+		if value > 0 {
+			byValue = append(byValue, node)
+		}
+	}
+	sort.SliceStable(byValue, func(i, j int) bool {
+		return g.Values[byValue[i]] > g.Values[byValue[j]]
+	})
+
+	minDistance := Unreachable
+	for _, a := range byValue {
+		for _, b := range byValue {
+			if a != b {
+				minDistance = intMin(minDistance, g.Distances.Get(a, b))
+			}
+		}
+	}
+
+	return &GreedyBound{
+		ByValue:     byValue,
+		MinDistance: minDistance,
+	}
+}
+
+func (b *GreedyBound) UpperBound(g *Graph, s SearchState, config Config) int {
+	// The time left after each agent opens its next valve. The first valve
+	// needs at least the distance to the closest unopened valve.
+	nextTimes := make([]int, 0, s.AgentsLeft+1)
+	nextTimes = append(nextTimes, s.TimeLeft-b.closestUnopened(g, s.CurrNode, s.Visited)-config.OpenTime)
+	if s.AgentsLeft > 0 {
+		fromStart := config.AgentMinutes - b.closestUnopened(g, g.Indices[config.StartNode], s.Visited) - config.OpenTime
+		for i := 0; i < s.AgentsLeft; i++ {
+			nextTimes = append(nextTimes, fromStart)
+		}
+	}
+
+	extraFlow := 0
+	for _, node := range b.ByValue {
+		if s.Visited.Contains(node) {
+			continue
+		}
+		// Give this valve to whichever agent would get to it soonest.
+		agent := 0
+		for i, t := range nextTimes {
+			if t > nextTimes[agent] {
+				agent = i
+			}
+		}
+		if nextTimes[agent] <= 0 {
+			break
+		}
+		extraFlow += g.Values[node] * nextTimes[agent]
+		nextTimes[agent] -= b.MinDistance + config.OpenTime
+	}
+	return s.Flow + extraFlow
+}
+
+func (b *GreedyBound) closestUnopened(g *Graph, from int, visited ValveSet) int {
+	closest := Unreachable
+	for _, node := range b.ByValue {
+		if !visited.Contains(node) {
+			closest = intMin(closest, g.Distances.Get(from, node))
+		}
+	}
+	return closest
+}
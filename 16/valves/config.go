@@ -0,0 +1,66 @@
+package valves
+
+import (
+	"fmt"
+)
+
+// Settings for building the graph and searching it.
+type Config struct {
+	// The node every agent starts from.
+	StartNode string
+	// How many minutes each agent has.
+	AgentMinutes int
+	// How many minutes it takes to open a valve once an agent gets to it.
+	OpenTime int
+	// How many agents work together, taking turns to do their own search.
+	NumAgents int
+}
+
+// Part one of the puzzle: just me, with 30 minutes.
+var PartOneConfig = Config{
+	StartNode:    "AA",
+	AgentMinutes: 30,
+	OpenTime:     1,
+	NumAgents:    1,
+}
+
+// Part two of the puzzle: me and the elephant, with 26 minutes each.
+var PartTwoConfig = Config{
+	StartNode:    "AA",
+	AgentMinutes: 26,
+	OpenTime:     1,
+	NumAgents:    2,
+}
+
+// Checks that the graph can be searched with this config.
+func (c Config) Validate(g *Graph) error {
+	if _, ok := g.Indices[c.StartNode]; !ok {
+		return fmt.Errorf("start node %s is not in the graph", c.StartNode)
+	}
+	if err := c.checkCounts(); err != nil {
+		return err
+	}
+	if g.Timed != nil && g.Timed.Horizon < c.AgentMinutes {
+		return fmt.Errorf("travel times only worked out for %d minutes, but agents have %d", g.Timed.Horizon, c.AgentMinutes)
+	}
+	return checkValveCount(g)
+}
+
+// Panics if the graph can't be searched with this config. The solvers call
+// this, so call Validate first to get an error instead.
+func (c Config) check(g *Graph) {
+	if err := c.Validate(g); err != nil {
+		panic(err)
+	}
+}
+
+// Checks the parts of the config that don't depend on the graph.
+func (c Config) checkCounts() error {
+	if c.AgentMinutes < 0 || c.OpenTime < 0 {
+		return fmt.Errorf("times can't be negative, got %d minutes per agent and %d to open a valve", c.AgentMinutes, c.OpenTime)
+	}
+	if c.NumAgents < 0 {
+		return fmt.Errorf("number of agents can't be negative, got %d", c.NumAgents)
+	}
+	return nil
+}
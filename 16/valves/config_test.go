@@ -0,0 +1,34 @@
+package valves
+
+import (
+	"testing"
+)
+
+func TestConfigValidate(t *testing.T) {
+	g := exampleGraph(t, PartTwoConfig)
+	cases := []struct {
+		name   string
+		modify func(c *Config)
+		valid  bool
+	}{
+		{"part two", func(c *Config) {}, true},
+		{"no agents", func(c *Config) { c.NumAgents = 0 }, true},
+		{"missing start node", func(c *Config) { c.StartNode = "ZZ" }, false},
+		{"negative minutes", func(c *Config) { c.AgentMinutes = -1 }, false},
+		{"negative open time", func(c *Config) { c.OpenTime = -1 }, false},
+		{"negative agents", func(c *Config) { c.NumAgents = -1 }, false},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			config := PartTwoConfig
+			c.modify(&config)
+			err := config.Validate(g)
+			if c.valid && err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+			if !c.valid && err == nil {
+				t.Errorf("Expected an error")
+			}
+		})
+	}
+}
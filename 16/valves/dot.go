@@ -0,0 +1,151 @@
+package valves
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// Colours for each agent's route, reused if there are more agents than this.
+var routeColors = []string{"red", "blue", "darkgreen", "orange", "purple"}
+
+// Options for WriteDot.
+type DotOptions struct {
+	// Node to highlight as the start. Nothing is highlighted if it's empty.
+	StartNode string
+	// If set, the route each agent takes is drawn over the graph, with the
+	// tunnels numbered in the order they're walked and labelled with the
+	// minutes they're walked in.
+	Schedule *Schedule
+}
+
+// Keeps the first error from a series of writes, so they can all be checked
+// at the end.
+type errWriter struct {
+	w   io.Writer
+	err error
+}
+
+func (ew *errWriter) printf(format string, args ...interface{}) {
+	if ew.err != nil {
+		return
+	}
+	_, ew.err = fmt.Fprintf(ew.w, format, args...)
+}
+
+// Writes the graph in Graphviz's dot format. Each tunnel is drawn once, since
+// they go both ways, and each node is labelled with its flow rate. This works
+// on the graph as it's parsed, and after RemoveZeroValueNodes.
+func (g *Graph) WriteDot(w io.Writer, opts DotOptions) error {
+	names := make([]string, 0, len(g.Nodes))
+	for name := range g.Nodes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var routes [][]routeStep
+	opened := make(map[string]ValveOpening)
+	if opts.Schedule != nil {
+		if _, ok := g.Nodes[opts.StartNode]; !ok {
+			return fmt.Errorf("start node %q is needed to draw the route, but isn't in the graph", opts.StartNode)
+		}
+		for _, openings := range opts.Schedule.Agents {
+			route, err := g.route(opts.StartNode, openings)
+			if err != nil {
+				return err
+			}
+			routes = append(routes, route)
+			for _, o := range openings {
+				opened[o.Valve] = o
+			}
+		}
+	}
+
+	ew := &errWriter{w: w}
+	ew.printf("graph {\n")
+	ew.printf("\tnode [shape=circle];\n")
+
+	for _, name := range names {
+		label := fmt.Sprintf("%s\\nrate=%d", name, g.Nodes[name].Value)
+		attrs := []string{}
+		if o, ok := opened[name]; ok {
+			label += fmt.Sprintf("\\nopened min %d", o.OpenedMinute)
+			attrs = append(attrs, fmt.Sprintf("color=%s", routeColor(o.Agent)))
+		}
+		attrs = append([]string{fmt.Sprintf("label=\"%s\"", label)}, attrs...)
+		if name == opts.StartNode {
+			attrs = append(attrs, "shape=doublecircle", "style=filled", "fillcolor=lightblue")
+		} else if g.Nodes[name].Value > 0 {
+			attrs = append(attrs, "style=filled", "fillcolor=lightyellow")
+		}
+		ew.printf("\t%q [%s];\n", name, strings.Join(attrs, ", "))
+	}
+
+	// Only write each tunnel in one direction.
+	for _, name := range names {
+		node := g.Nodes[name]
+		for _, destName := range node.SortedEdgeNames() {
+			if destName < name {
+				continue
+			}
+			ew.printf("\t%q -- %q [label=\"%s\"];\n", name, destName, node.Edges[destName].Label())
+		}
+	}
+
+	for agent, route := range routes {
+		for i, step := range route {
+			minutes := fmt.Sprintf("min %d", step.FirstMinute)
+			if step.LastMinute != step.FirstMinute {
+				minutes = fmt.Sprintf("min %d-%d", step.FirstMinute, step.LastMinute)
+			}
+			ew.printf("\t%q -- %q [dir=forward, color=%s, penwidth=2, fontcolor=%s, label=\"%d: %s\"];\n",
+				step.From, step.To, routeColor(agent), routeColor(agent), i+1, minutes)
+		}
+	}
+
+	ew.printf("}\n")
+	return ew.err
+}
+
+func routeColor(agent int) string {
+	return routeColors[agent%len(routeColors)]
+}
+
+// One tunnel walked along an agent's route.
+type routeStep struct {
+	From string
+	To   string
+	// The minutes spent walking the tunnel.
+	FirstMinute int
+	LastMinute  int
+}
+
+// Follows the shortest path through the graph between each valve an agent
+// opens, timing each tunnel from the schedule.
+func (g *Graph) route(start string, openings []ValveOpening) ([]routeStep, error) {
+	steps := make([]routeStep, 0)
+	curr := start
+	minute := 0
+	for _, o := range openings {
+		path, ok := g.ShortestPathsAt(curr, minute).PathTo(o.Valve)
+		if !ok {
+			return nil, fmt.Errorf("no route from %s to %s in the graph", curr, o.Valve)
+		}
+		for i := 1; i < len(path); i++ {
+			edge := g.Nodes[path[i-1]].Edges[path[i]]
+			// This must be open, otherwise there wouldn't be a path.
+			minute, _ = edge.NextOpen(minute)
+			steps = append(steps, routeStep{
+				From:        path[i-1],
+				To:          path[i],
+				FirstMinute: minute + 1,
+				LastMinute:  minute + edge.Cost,
+			})
+			minute += edge.Cost
+		}
+		curr = o.Valve
+		minute = o.OpenedMinute
+	}
+	return steps, nil
+}
@@ -0,0 +1,49 @@
+package valves
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestWriteDot(t *testing.T) {
+	g := graphFromLines([]string{
+		"Valve AA has flow rate=0; tunnels lead to valves BB, CC",
+		"Valve BB has flow rate=5; tunnels lead to valves AA, CC",
+		"Valve CC has flow rate=0; tunnels lead to valves AA, BB",
+	})
+	schedule := &Schedule{
+		TotalPressure: 40,
+		Agents: [][]ValveOpening{{
+			{Agent: 0, Valve: "BB", ArrivedMinute: 1, OpenedMinute: 2, Pressure: 40},
+		}},
+	}
+
+	var sb strings.Builder
+	err := g.WriteDot(&sb, DotOptions{StartNode: "AA", Schedule: schedule})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	out := sb.String()
+
+	expectedLines := []string{
+		"graph {",
+		`"AA" [label="AA\nrate=0", shape=doublecircle, style=filled, fillcolor=lightblue];`,
+		`"BB" [label="BB\nrate=5\nopened min 2", color=red, style=filled, fillcolor=lightyellow];`,
+		`"AA" -- "BB" [label="1"];`,
+		`"AA" -- "BB" [dir=forward, color=red, penwidth=2, fontcolor=red, label="1: min 1"];`,
+	}
+	for _, line := range expectedLines {
+		if !strings.Contains(out, line) {
+			t.Errorf("Expected output to contain %s, got:\n%s", line, out)
+		}
+	}
+	// Each tunnel should only be written once.
+	if strings.Contains(out, `"BB" -- "AA" [label`) {
+		t.Errorf("Expected each tunnel once, got:\n%s", out)
+	}
+
+	err = g.WriteDot(&sb, DotOptions{StartNode: "ZZ", Schedule: schedule})
+	if err == nil {
+		t.Errorf("Expected an error for a route from a missing start node")
+	}
+}
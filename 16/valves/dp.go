@@ -0,0 +1,135 @@
+package valves
+
+import (
+	"sort"
+)
+
+// Finds the best flow a single agent can get for each set of valves it ends up
+// opening, by walking every path the agent could take.
+func BestFlowPerSubset(g *Graph, config Config) map[ValveSet]SearchState {
+	config.check(g)
+
+	best := make(map[ValveSet]SearchState)
+
+	var visit func(s SearchState)
+	visit = func(s SearchState) {
+		if prev, ok := best[s.Visited]; !ok || s.Flow > prev.Flow {
+			best[s.Visited] = s
+		}
+		for _, subState := range s.GetSubStates(g, config) {
+			visit(subState)
+		}
+	}
+	// Search as if there's only one agent.
+	single := config
+	single.NumAgents = 1
+	visit(initialState(g, single))
+
+	return best
+}
+
+// The best way found so far for some number of agents to open a set of valves
+// between them.
+type subsetResult struct {
+	Visited ValveSet
+	Flow    int
+	// The final state of each agent's search.
+	Agents []SearchState
+}
+
+// Sorts results from most to least flow, breaking ties by the valves opened so
+// the order doesn't depend on map iteration.
+func sortSubsetResults(results []subsetResult) {
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Flow != results[j].Flow {
+			return results[i].Flow > results[j].Flow
+		}
+		return results[i].Visited < results[j].Visited
+	})
+}
+
+// Pairs up results that open disjoint sets of valves, keeping the best pair
+// for each combined set. If onlyBest is set, only the single best pair is
+// kept, which lets us skip most of the pairs.
+func combineDisjoint(first, second []subsetResult, onlyBest bool) []subsetResult {
+	combined := make(map[ValveSet]subsetResult)
+	bestFlow := -1
+	for _, a := range first {
+		for _, b := range second {
+			flow := a.Flow + b.Flow
+			// Both lists are sorted, so nothing later in this list can do
+			// better.
+			if onlyBest && flow <= bestFlow {
+				break
+			}
+			if a.Visited&b.Visited != 0 {
+				continue
+			}
+			visited := a.Visited | b.Visited
+			if prev, ok := combined[visited]; ok && prev.Flow >= flow {
+				continue
+			}
+			agents := make([]SearchState, 0, len(a.Agents)+len(b.Agents))
+			agents = append(agents, a.Agents...)
+			agents = append(agents, b.Agents...)
+			combined[visited] = subsetResult{
+				Visited: visited,
+				Flow:    flow,
+				Agents:  agents,
+			}
+			if flow > bestFlow {
+				bestFlow = flow
+			}
+		}
+	}
+
+	results := make([]subsetResult, 0, len(combined))
+	for _, r := range combined {
+		results = append(results, r)
+	}
+	sortSubsetResults(results)
+	if onlyBest && len(results) > 0 {
+		results = results[:1]
+	}
+	return results
+}
+
+// Same as Solve, but instead of branch and bound it works out the best flow
+// for every set of valves a single agent could open, then picks the best
+// combination of disjoint sets for the agents.
+func SolveDP(g *Graph, config Config) Schedule {
+	if config.NumAgents < 1 {
+		return Schedule{}
+	}
+
+	single := make([]subsetResult, 0)
+	for visited, s := range BestFlowPerSubset(g, config) {
+		single = append(single, subsetResult{
+			Visited: visited,
+			Flow:    s.Flow,
+			Agents:  []SearchState{s},
+		})
+	}
+	sortSubsetResults(single)
+
+	combined := single
+	for agent := 1; agent < config.NumAgents; agent++ {
+		combined = combineDisjoint(combined, single, agent == config.NumAgents-1)
+	}
+	best := combined[0]
+
+	// Each agent's search was done as if it was the only agent, so fix up the
+	// agent numbers.
+	schedule := Schedule{
+		TotalPressure: best.Flow,
+		Agents:        make([][]ValveOpening, config.NumAgents),
+	}
+	for i, s := range best.Agents {
+		openings := ScheduleFromState(s, 1).Agents[0]
+		for j := range openings {
+			openings[j].Agent = i
+		}
+		schedule.Agents[i] = openings
+	}
+	return schedule
+}
@@ -0,0 +1,132 @@
+package valves
+
+import (
+	"context"
+)
+
+type SearchState struct {
+	// Index of the node the current agent is at.
+	CurrNode int
+	// All the nodes we've stopped at and opened a valve at. This doesn't include nodes we passed through to get to other nodes.
+	Visited ValveSet
+
+	// How many agents still have to do their own search from the start node
+	// once the current agent is done. The agents take turns, so the second
+	// agent (e.g. the elephant) only starts once the first one has stopped.
+	AgentsLeft int
+	// Which agent is currently moving, counting up from 0.
+	Agent    int
+	Flow     int
+	TimeLeft int
+
+	// The most recent valve opened, which links back to all the ones before
+	// it. Sharing the history between states saves copying it for every
+	// substate.
+	LastOpened *OpeningHistory
+}
+
+func (s SearchState) Value() int {
+	return s.Flow
+}
+
+func (s SearchState) GetSubStates(g *Graph, config Config) []SearchState {
+	subStates := make([]SearchState, 0)
+
+	// Create a new state for visiting every other node.
+	for node, value := range g.Values {
+		// No point revisiting a node, or opening a valve with no flow.
+		if s.Visited.Contains(node) || value == 0 {
+			continue
+		}
+		// Add on the time to open the node.
+		elapsed := config.AgentMinutes - s.TimeLeft
+		dist := g.TravelTime(s.CurrNode, node, elapsed) + config.OpenTime
+		if dist > s.TimeLeft {
+			continue
+		}
+
+		newTimeLeft := s.TimeLeft - dist
+		pressure := value * newTimeLeft
+		newFlow := s.Flow + pressure
+
+		opened := &OpeningHistory{
+			Opening: ValveOpening{
+				Agent:         s.Agent,
+				Valve:         g.Names[node],
+				ArrivedMinute: config.AgentMinutes - newTimeLeft - config.OpenTime,
+				OpenedMinute:  config.AgentMinutes - newTimeLeft,
+				Pressure:      pressure,
+			},
+			Prev: s.LastOpened,
+		}
+
+		newState := SearchState{
+			CurrNode:   node,
+			Visited:    s.Visited.With(node),
+			AgentsLeft: s.AgentsLeft,
+			Agent:      s.Agent,
+			Flow:       newFlow,
+			TimeLeft:   newTimeLeft,
+			LastOpened: opened,
+		}
+		subStates = append(subStates, newState)
+	}
+
+	// And we might also be able to stop here and hand over to the next agent.
+	if s.AgentsLeft > 0 {
+		newState := SearchState{
+			CurrNode:   g.Indices[config.StartNode],
+			Visited:    s.Visited,
+			AgentsLeft: s.AgentsLeft - 1,
+			Agent:      s.Agent + 1,
+			Flow:       s.Flow,
+			TimeLeft:   config.AgentMinutes,
+			LastOpened: s.LastOpened,
+		}
+		subStates = append(subStates, newState)
+	}
+
+	return subStates
+}
+
+// The state every search starts from: the first agent at the start node with
+// nothing opened yet. The start valve isn't marked as visited, so if it has
+// any flow it can be opened straight away.
+func initialState(g *Graph, config Config) SearchState {
+	return SearchState{
+		CurrNode:   g.Indices[config.StartNode],
+		Visited:    0,
+		AgentsLeft: config.NumAgents - 1,
+		Flow:       0,
+		TimeLeft:   config.AgentMinutes,
+	}
+}
+
+// Search statistics, to compare how well different bounds prune the search.
+type SearchStats struct {
+	// States whose substates were added to the search.
+	Searched int
+	// States that were pruned because their upper bound was below the best.
+	Skipped int
+}
+
+// Finds the schedule that releases the most pressure with the agents in the
+// config working together, each starting from the start node.
+func Solve(g *Graph, config Config) Schedule {
+	schedule, _ := SolveWithBound(g, config, NewGreedyBound(g))
+	return schedule
+}
+
+// Same as Solve, but uses the given bound to prune the search, and also
+// returns statistics about the search.
+func SolveWithBound(g *Graph, config Config, bound Bound) (Schedule, SearchStats) {
+	result := SolveAnytime(context.Background(), g, config, AnytimeOptions{Bound: bound})
+	return result.Best, result.Stats
+}
+
+func intMin(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,333 @@
+package valves
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+const exampleInput = `Valve AA has flow rate=0; tunnels lead to valves DD, II, BB
+Valve BB has flow rate=13; tunnels lead to valves CC, AA
+Valve CC has flow rate=2; tunnels lead to valves DD, BB
+Valve DD has flow rate=20; tunnels lead to valves CC, AA, EE
+Valve EE has flow rate=3; tunnels lead to valves FF, DD
+Valve FF has flow rate=0; tunnels lead to valves EE, GG
+Valve GG has flow rate=0; tunnels lead to valves FF, HH
+Valve HH has flow rate=22; tunnel leads to valve GG
+Valve II has flow rate=0; tunnels lead to valves AA, JJ
+Valve JJ has flow rate=21; tunnel leads to valve II
+`
+
+func exampleGraph(t testing.TB, config Config) *Graph {
+	g, err := ParseGraph(strings.NewReader(exampleInput))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	g.RemoveZeroValueNodes(config.StartNode)
+	g.CalculateDistancesToEachNode()
+	return g
+}
+
+func TestSolveExample(t *testing.T) {
+	cases := []struct {
+		name     string
+		config   Config
+		expected int
+	}{
+		{"part 1", PartOneConfig, 1651},
+		{"part 2", PartTwoConfig, 1707},
+	}
+
+	for _, c := range cases {
+		g := exampleGraph(t, c.config)
+		solvers := map[string]func() Schedule{
+			"greedy bound": func() Schedule {
+				return Solve(g, c.config)
+			},
+			"naive bound": func() Schedule {
+				s, _ := SolveWithBound(g, c.config, NaiveBound{})
+				return s
+			},
+			"parallel": func() Schedule {
+				s, _ := SolveParallel(g, c.config, NewGreedyBound(g), 4)
+				return s
+			},
+			"dp": func() Schedule {
+				return SolveDP(g, c.config)
+			},
+		}
+		for name, solver := range solvers {
+			t.Run(c.name+" "+name, func(t *testing.T) {
+				schedule := solver()
+				if schedule.TotalPressure != c.expected {
+					t.Errorf("Expected %d, got %d", c.expected, schedule.TotalPressure)
+				}
+				checkSchedule(t, g, c.config, schedule)
+			})
+		}
+	}
+}
+
+// The valve at the start node should be opened like any other.
+func TestSolveStartValve(t *testing.T) {
+	input := `Valve AA has flow rate=10; tunnel leads to valve BB
+Valve BB has flow rate=0; tunnel leads to valve AA
+`
+	_, g := graphFromInput(t, input, PartTwoConfig)
+	// Opened after the first minute, then releases 10 for each of the other
+	// 25 minutes.
+	if s := Solve(g, PartTwoConfig); s.TotalPressure != 250 {
+		t.Errorf("Expected 250, got %d", s.TotalPressure)
+	}
+	if s := SolveDP(g, PartTwoConfig); s.TotalPressure != 250 {
+		t.Errorf("Expected 250 with DP, got %d", s.TotalPressure)
+	}
+
+	config := PartOneConfig
+	config.StartNode = "DD"
+	raw, g := graphFromInput(t, exampleInput, config)
+	expected := bruteForce(raw, config)
+	if s := Solve(g, config); s.TotalPressure != expected {
+		t.Errorf("Expected %d starting at DD, got %d", expected, s.TotalPressure)
+	}
+}
+
+// Checks that a schedule could actually be followed, and that it releases as
+// much pressure as it says.
+func checkSchedule(t *testing.T, g *Graph, config Config, schedule Schedule) {
+	t.Helper()
+	if len(schedule.Agents) != config.NumAgents {
+		t.Fatalf("Expected %d agents, got %d", config.NumAgents, len(schedule.Agents))
+	}
+	opened := make(map[string]bool)
+	total := 0
+	for agent, openings := range schedule.Agents {
+		curr := config.StartNode
+		minute := 0
+		for _, o := range openings {
+			if opened[o.Valve] {
+				t.Errorf("Valve %s opened twice", o.Valve)
+			}
+			opened[o.Valve] = true
+
+			dist := g.Distances.Get(g.Indices[curr], g.Indices[o.Valve])
+			if o.ArrivedMinute != minute+dist || o.OpenedMinute != o.ArrivedMinute+config.OpenTime {
+				t.Errorf("Agent %d can't get from %s to %s in time: %+v", agent, curr, o.Valve, o)
+			}
+			pressure := g.Nodes[o.Valve].Value * (config.AgentMinutes - o.OpenedMinute)
+			if o.Pressure != pressure || o.OpenedMinute > config.AgentMinutes {
+				t.Errorf("Expected %s to release %d, got %+v", o.Valve, pressure, o)
+			}
+			total += o.Pressure
+			curr = o.Valve
+			minute = o.OpenedMinute
+		}
+	}
+	if total != schedule.TotalPressure {
+		t.Errorf("Expected total pressure %d, got %d", total, schedule.TotalPressure)
+	}
+}
+
+// Makes the input for a random connected graph in the puzzle's format, with
+// AA as the start.
+func randomInput(r *rand.Rand, numNodes int, numValves int) string {
+	names := make([]string, numNodes)
+	for i := range names {
+		names[i] = fmt.Sprintf("%c%c", 'A'+i/26, 'A'+i%26)
+	}
+	edges := make([]map[int]bool, numNodes)
+	for i := range edges {
+		edges[i] = make(map[int]bool)
+	}
+	connect := func(a, b int) {
+		if a != b {
+			edges[a][b] = true
+			edges[b][a] = true
+		}
+	}
+	// A random tree so everything's connected, plus some extra tunnels.
+	for i := 1; i < numNodes; i++ {
+		connect(i, r.Intn(i))
+	}
+	for i := 0; i < numNodes/3; i++ {
+		connect(r.Intn(numNodes), r.Intn(numNodes))
+	}
+	values := make([]int, numNodes)
+	for _, i := range r.Perm(numNodes - 1)[:numValves] {
+		values[i+1] = 1 + r.Intn(25)
+	}
+
+	var sb strings.Builder
+	for i, name := range names {
+		dests := make([]string, 0)
+		for j := range names {
+			if edges[i][j] {
+				dests = append(dests, names[j])
+			}
+		}
+		sb.WriteString(fmt.Sprintf("Valve %s has flow rate=%d; tunnels lead to valves %s\n", name, values[i], strings.Join(dests, ", ")))
+	}
+	return sb.String()
+}
+
+func graphFromInput(t testing.TB, input string, config Config) (raw *Graph, compressed *Graph) {
+	raw, err := ParseGraph(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v\n%s", err, input)
+	}
+	compressed, _ = ParseGraph(strings.NewReader(input))
+	compressed.RemoveZeroValueNodes(config.StartNode)
+	compressed.CalculateDistancesToEachNode()
+	return raw, compressed
+}
+
+// Finds the best flow by trying every order of opening the valves, with no
+// pruning. It works on the graph before any nodes are removed, and finds the
+// distances with a plain breadth first search, so it shares as little as
+// possible with the real solver.
+func bruteForce(g *Graph, config Config) int {
+	distances := make(map[string]map[string]int)
+	for name := range g.Nodes {
+		dist := map[string]int{name: 0}
+		queue := []string{name}
+		for len(queue) > 0 {
+			curr := queue[0]
+			queue = queue[1:]
+			for dest := range g.Nodes[curr].Edges {
+				if _, ok := dist[dest]; !ok {
+					dist[dest] = dist[curr] + 1
+					queue = append(queue, dest)
+				}
+			}
+		}
+		distances[name] = dist
+	}
+
+	opened := make(map[string]bool)
+	var visit func(curr string, timeLeft int, agentsLeft int) int
+	visit = func(curr string, timeLeft int, agentsLeft int) int {
+		best := 0
+		// Stop here and let the next agent go.
+		if agentsLeft > 0 {
+			best = visit(config.StartNode, config.AgentMinutes, agentsLeft-1)
+		}
+		for name, node := range g.Nodes {
+			if opened[name] || node.Value == 0 {
+				continue
+			}
+			dist, ok := distances[curr][name]
+			newTimeLeft := timeLeft - dist - config.OpenTime
+			if !ok || newTimeLeft < 0 {
+				continue
+			}
+			opened[name] = true
+			flow := node.Value*newTimeLeft + visit(name, newTimeLeft, agentsLeft)
+			opened[name] = false
+			if flow > best {
+				best = flow
+			}
+		}
+		return best
+	}
+	return visit(config.StartNode, config.AgentMinutes, config.NumAgents-1)
+}
+
+func checkAgainstBruteForce(t *testing.T, seed int64) {
+	r := rand.New(rand.NewSource(seed))
+	config := Config{
+		StartNode:    "AA",
+		AgentMinutes: 8 + r.Intn(10),
+		OpenTime:     1,
+		NumAgents:    1 + r.Intn(3),
+	}
+	numNodes := 3 + r.Intn(8)
+	input := randomInput(r, numNodes, 1+r.Intn(intMin(numNodes-1, 6)))
+	// Sometimes start somewhere else, which might have a valve to open.
+	if r.Intn(2) == 0 {
+		start := r.Intn(numNodes)
+		config.StartNode = fmt.Sprintf("%c%c", 'A'+start/26, 'A'+start%26)
+	}
+	raw, g := graphFromInput(t, input, config)
+
+	expected := bruteForce(raw, config)
+	results := map[string]int{
+		"greedy": Solve(g, config).TotalPressure,
+		"naive":  func() int { s, _ := SolveWithBound(g, config, NaiveBound{}); return s.TotalPressure }(),
+		"dp":     SolveDP(g, config).TotalPressure,
+	}
+	for name, got := range results {
+		if got != expected {
+			t.Errorf("Seed %d, %s: expected %d, got %d, config %+v, graph:\n%s", seed, name, expected, got, config, input)
+		}
+	}
+}
+
+func TestSolveRandomGraphs(t *testing.T) {
+	for seed := int64(0); seed < 100; seed++ {
+		checkAgainstBruteForce(t, seed)
+	}
+}
+
+func FuzzSolve(f *testing.F) {
+	for seed := int64(0); seed < 10; seed++ {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, seed int64) {
+		checkAgainstBruteForce(t, seed)
+	})
+}
+
+func benchmarkGraph(b *testing.B, config Config) *Graph {
+	r := rand.New(rand.NewSource(16))
+	_, g := graphFromInput(b, randomInput(r, 50, 12), config)
+	return g
+}
+
+func BenchmarkSolve(b *testing.B) {
+	g := benchmarkGraph(b, PartTwoConfig)
+	bounds := map[string]Bound{
+		"naive":  NaiveBound{},
+		"greedy": NewGreedyBound(g),
+	}
+	for name, bound := range bounds {
+		b.Run(name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				SolveWithBound(g, PartTwoConfig, bound)
+			}
+		})
+	}
+}
+
+func BenchmarkSolveParallel(b *testing.B) {
+	g := benchmarkGraph(b, PartTwoConfig)
+	bound := NewGreedyBound(g)
+	for i := 0; i < b.N; i++ {
+		SolveParallel(g, PartTwoConfig, bound, 0)
+	}
+}
+
+func BenchmarkSolveDP(b *testing.B) {
+	g := benchmarkGraph(b, PartTwoConfig)
+	for i := 0; i < b.N; i++ {
+		SolveDP(g, PartTwoConfig)
+	}
+}
+
+func BenchmarkSolveAnytime(b *testing.B) {
+	g := benchmarkGraph(b, PartTwoConfig)
+	for i := 0; i < b.N; i++ {
+		SolveAnytime(context.Background(), g, PartTwoConfig, AnytimeOptions{MaxExpanded: 10000})
+	}
+}
+
+func BenchmarkCalculateDistancesToEachNode(b *testing.B) {
+	r := rand.New(rand.NewSource(16))
+	input := randomInput(r, 50, 12)
+	for i := 0; i < b.N; i++ {
+		g, _ := ParseGraph(strings.NewReader(input))
+		g.RemoveZeroValueNodes("AA")
+		g.CalculateDistancesToEachNode()
+	}
+}
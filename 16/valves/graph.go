@@ -0,0 +1,208 @@
+package valves
+
+import (
+	"fmt"
+	"os"
+	"sort"
+)
+
+type Edge struct {
+	DestName string
+	Cost     int
+	// When the tunnel can be entered, sorted by start time. If there are none
+	// the tunnel is always open.
+	Windows []Window
+}
+
+type Node struct {
+	Name  string
+	Value int
+	Edges map[string]*Edge
+}
+
+// Parse an example string like this:
+// Valve GJ has flow rate=14; tunnels lead to valves UV, AO, MM, UD, GM
+// Panics if the line is invalid. Use ParseNode to get an error instead.
+func NodeFromString(s string) *Node {
+	node, err := ParseNode(s)
+	if err != nil {
+		panic(err)
+	}
+	return node
+}
+
+type Graph struct {
+	Nodes map[string]*Node
+
+	// Dense indices for each node, so the search can use bitmasks and slices
+	// instead of maps. Set up by CalculateDistancesToEachNode, in name order.
+	Names   []string
+	Indices map[string]int
+	Values  []int
+
+	// The shortest distance between each pair of nodes, ignoring when the
+	// tunnels are open. This is never more than the real travel time, so it
+	// can be used for bounds.
+	Distances DistanceMatrix
+	// The travel times for each departure minute, if some tunnels are only
+	// open some of the time. Set up by CalculateTimedDistances.
+	Timed *TimedDistances
+}
+
+// Removes nodes with no flow, apart from keep, joining up their neighbours.
+func (g *Graph) RemoveZeroValueNodes(keep string) {
+	// Optimize the graph by removing nodes with value of 0.
+	// First get all the keys because we're going to modify the map
+	// while iterating over it.
+	nodeNames := make([]string, len(g.Nodes))
+	i := 0
+	for nodeName := range g.Nodes {
+		nodeNames[i] = nodeName
+		i++
+	}
+
+	for _, nodeName := range nodeNames {
+		node := g.Nodes[nodeName]
+		if node.Value != 0 {
+			continue
+		}
+		// Special case the start node because we need it for the solution.
+		if node.Name == keep {
+			continue
+		}
+		// Tunnels that open and close can't be joined into one tunnel.
+		if !g.canDeleteNode(node) {
+			continue
+		}
+		g.DeleteNode(nodeName)
+	}
+}
+
+func (g *Graph) DeleteNode(nodeName string) {
+	node := g.Nodes[nodeName]
+	// Remove this node
+	delete(g.Nodes, nodeName)
+	// Remove this node from the other node's edges
+	for _, edge := range node.Edges {
+		otherNode := g.Nodes[edge.DestName]
+		delete(otherNode.Edges, nodeName)
+	}
+
+	// Connect all the nodes that pass through this node
+	// to each other.
+	for _, firstEdge := range node.Edges {
+		for _, secondEdge := range node.Edges {
+			if firstEdge == secondEdge {
+				continue
+			}
+
+			// Connect the two nodes
+			firstNode := g.Nodes[firstEdge.DestName]
+			secondNode := g.Nodes[secondEdge.DestName]
+
+			newCost := firstEdge.Cost + secondEdge.Cost
+
+			// Check if the edge already exists, and maybe update the cost
+			if _, ok := firstNode.Edges[secondNode.Name]; ok {
+				firstNode.Edges[secondNode.Name].Cost = intMin(firstNode.Edges[secondNode.Name].Cost, newCost)
+				secondNode.Edges[firstNode.Name].Cost = intMin(secondNode.Edges[firstNode.Name].Cost, newCost)
+				continue
+			}
+
+			firstNode.Edges[secondNode.Name] = &Edge{
+				DestName: secondNode.Name,
+				Cost:     newCost,
+			}
+			secondNode.Edges[firstNode.Name] = &Edge{
+				DestName: firstNode.Name,
+				Cost:     newCost,
+			}
+		}
+	}
+}
+
+// Assigns each node a dense index, and works out the distance between every
+// pair of nodes.
+func (g *Graph) CalculateDistancesToEachNode() {
+	g.Names = make([]string, 0, len(g.Nodes))
+	for name := range g.Nodes {
+		g.Names = append(g.Names, name)
+	}
+	sort.Strings(g.Names)
+	g.Indices = make(map[string]int)
+	g.Values = make([]int, len(g.Names))
+	for i, name := range g.Names {
+		g.Indices[name] = i
+		g.Values[i] = g.Nodes[name].Value
+	}
+
+	distances := NewDistanceMatrix(len(g.Names))
+	for from, paths := range g.AllPairsShortestPaths() {
+		for to, dist := range paths.Dist {
+			distances.Set(g.Indices[from], g.Indices[to], dist)
+		}
+	}
+	g.Distances = distances
+}
+
+func (g *Graph) PrintNodes() {
+	for _, node := range g.Nodes {
+		fmt.Println(node)
+	}
+}
+
+// Writes the graph to a Graphviz file. See WriteDot for the options.
+func (g *Graph) OutputAsDotFile(filename string, opts DotOptions) error {
+	f, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	if err := g.WriteDot(f, opts); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// Reads the graph from a file as it is, without removing any nodes.
+func ReadGraphFile(filename string) (*Graph, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	graph, err := ParseGraph(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", filename, err)
+	}
+	return graph, nil
+}
+
+// Reads the graph from a file, removes the nodes that aren't worth visiting,
+// and works out the distances between the rest.
+func GraphFromFile(filename string, config Config) (*Graph, error) {
+	// The travel times with opening windows are worked out for each minute
+	// the agents have, so this has to be checked first.
+	if err := config.checkCounts(); err != nil {
+		return nil, err
+	}
+	graph, err := ReadGraphFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := graph.Nodes[config.StartNode]; !ok {
+		return nil, fmt.Errorf("%s: start node %s is not in the graph", filename, config.StartNode)
+	}
+
+	// Optimize the graph
+	graph.RemoveZeroValueNodes(config.StartNode)
+
+	// Calculate the distances between each node
+	graph.CalculateDistancesToEachNode()
+	if graph.HasWindows() {
+		graph.CalculateTimedDistances(config.AgentMinutes)
+	}
+
+	return graph, nil
+}
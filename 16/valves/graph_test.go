@@ -0,0 +1,110 @@
+package valves
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNodeFromString(t *testing.T) {
+	cases := []struct {
+		line  string
+		name  string
+		value int
+		edges []string
+	}{
+		{"Valve AA has flow rate=0; tunnels lead to valves DD, II, BB", "AA", 0, []string{"BB", "DD", "II"}},
+		{"Valve HH has flow rate=22; tunnel leads to valve GG", "HH", 22, []string{"GG"}},
+		{"Valve GJ has flow rate=14; tunnels lead to valves UV, AO, MM, UD, GM", "GJ", 14, []string{"AO", "GM", "MM", "UD", "UV"}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			node := NodeFromString(c.line)
+			if node.Name != c.name || node.Value != c.value {
+				t.Errorf("Expected %s with %d, got %s with %d", c.name, c.value, node.Name, node.Value)
+			}
+			if !reflect.DeepEqual(node.SortedEdgeNames(), c.edges) {
+				t.Errorf("Expected edges %v, got %v", c.edges, node.SortedEdgeNames())
+			}
+			for _, edge := range node.Edges {
+				if edge.Cost != 1 {
+					t.Errorf("Expected edge to %s to cost 1, got %d", edge.DestName, edge.Cost)
+				}
+			}
+		})
+	}
+}
+
+func TestNodeFromStringPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Expected a panic")
+		}
+	}()
+	NodeFromString("Valve AA has no flow rate")
+}
+
+func TestDeleteNode(t *testing.T) {
+	cases := []struct {
+		name   string
+		lines  []string
+		delete string
+		// The cost of each remaining edge, keyed by both ends.
+		costs map[[2]string]int
+	}{
+		{
+			name: "middle of a line",
+			lines: []string{
+				"Valve AA has flow rate=0; tunnel leads to valve BB",
+				"Valve BB has flow rate=0; tunnels lead to valves AA, CC",
+				"Valve CC has flow rate=1; tunnel leads to valve BB",
+			},
+			delete: "BB",
+			costs:  map[[2]string]int{{"AA", "CC"}: 2, {"CC", "AA"}: 2},
+		},
+		{
+			name: "centre of a star",
+			lines: []string{
+				"Valve AA has flow rate=0; tunnels lead to valves BB, CC, DD",
+				"Valve BB has flow rate=1; tunnel leads to valve AA",
+				"Valve CC has flow rate=1; tunnel leads to valve AA",
+				"Valve DD has flow rate=1; tunnel leads to valve AA",
+			},
+			delete: "AA",
+			costs: map[[2]string]int{
+				{"BB", "CC"}: 2, {"CC", "BB"}: 2,
+				{"BB", "DD"}: 2, {"DD", "BB"}: 2,
+				{"CC", "DD"}: 2, {"DD", "CC"}: 2,
+			},
+		},
+		{
+			name: "keeps the shorter edge",
+			lines: []string{
+				"Valve AA has flow rate=0; tunnels lead to valves BB, CC",
+				"Valve BB has flow rate=0; tunnels lead to valves AA, CC",
+				"Valve CC has flow rate=1; tunnels lead to valves AA, BB",
+			},
+			delete: "BB",
+			costs:  map[[2]string]int{{"AA", "CC"}: 1, {"CC", "AA"}: 1},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			g := graphFromLines(c.lines)
+			g.DeleteNode(c.delete)
+
+			if _, ok := g.Nodes[c.delete]; ok {
+				t.Errorf("Expected %s to be deleted", c.delete)
+			}
+			costs := make(map[[2]string]int)
+			for _, node := range g.Nodes {
+				for _, edge := range node.Edges {
+					costs[[2]string{node.Name, edge.DestName}] = edge.Cost
+				}
+			}
+			if !reflect.DeepEqual(costs, c.costs) {
+				t.Errorf("Expected edges %v, got %v", c.costs, costs)
+			}
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package valves
+
+import (
+	"fmt"
+	"math"
+	"math/bits"
+)
+
+// Distance between nodes that have no path between them. It's big enough to
+// be more than any time limit, but small enough to add to without overflowing.
+const Unreachable = math.MaxInt32
+
+// Distances between every pair of nodes, stored in one flat slice and indexed
+// by the nodes' dense indices.
+type DistanceMatrix struct {
+	Size  int
+	Dists []int
+}
+
+func NewDistanceMatrix(size int) DistanceMatrix {
+	dists := make([]int, size*size)
+	for i := range dists {
+		dists[i] = Unreachable
+	}
+	return DistanceMatrix{
+		Size:  size,
+		Dists: dists,
+	}
+}
+
+func (m DistanceMatrix) Get(from, to int) int {
+	return m.Dists[from*m.Size+to]
+}
+
+func (m DistanceMatrix) Set(from, to, dist int) {
+	m.Dists[from*m.Size+to] = dist
+}
+
+// The most nodes a ValveSet can hold.
+const MaxValves = 64
+
+// A set of nodes, as a bitmask of their dense indices.
+type ValveSet uint64
+
+func (v ValveSet) Contains(index int) bool {
+	return v&(1<<index) != 0
+}
+
+func (v ValveSet) With(index int) ValveSet {
+	return v | (1 << index)
+}
+
+func (v ValveSet) Without(index int) ValveSet {
+	return v &^ (1 << index)
+}
+
+func (v ValveSet) Len() int {
+	return bits.OnesCount64(uint64(v))
+}
+
+// Returns an error if the graph has too many nodes to fit in a ValveSet.
+func checkValveCount(g *Graph) error {
+	if len(g.Names) > MaxValves {
+		return fmt.Errorf("too many valves to search: %d, the most is %d", len(g.Names), MaxValves)
+	}
+	return nil
+}
@@ -0,0 +1,129 @@
+package valves
+
+import (
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// How many states to split the search into for each worker. More than one
+// each means a worker that finishes early can pick up more work.
+const statesPerWorker = 8
+
+// Same as SolveWithBound, but splits the search between several goroutines.
+// The best flow found so far is shared between the workers so they can all use
+// it for pruning. If workers is 0 or less, one worker is used for each CPU.
+//
+// The schedule returned is always the same for the same graph, no matter how
+// the work ends up being split, but the stats can differ from run to run.
+func SolveParallel(g *Graph, config Config, bound Bound, workers int) (Schedule, SearchStats) {
+	stats := SearchStats{}
+	if config.NumAgents < 1 {
+		return Schedule{}, stats
+	}
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+
+	config.check(g)
+
+	// Expand the search breadth first until there are enough states to share
+	// between the workers.
+	best := initialState(g, config)
+	frontier := []SearchState{best}
+	for len(frontier) > 0 && len(frontier) < workers*statesPerWorker {
+		nextFrontier := make([]SearchState, 0)
+		for _, s := range frontier {
+			if s.Flow > best.Flow {
+				best = s
+			}
+			if bound.UpperBound(g, s, config) < best.Flow {
+				stats.Skipped++
+				continue
+			}
+			nextFrontier = append(nextFrontier, s.GetSubStates(g, config)...)
+			stats.Searched++
+		}
+		frontier = nextFrontier
+	}
+
+	var bestFlow atomic.Int64
+	bestFlow.Store(int64(best.Flow))
+
+	// Each worker takes the next state from the frontier and searches
+	// everything below it.
+	results := make([]SearchState, len(frontier))
+	jobs := make(chan int)
+	var searched, skipped atomic.Int64
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				var subtreeStats SearchStats
+				results[i], subtreeStats = searchSubtree(g, frontier[i], config, bound, &bestFlow)
+				searched.Add(int64(subtreeStats.Searched))
+				skipped.Add(int64(subtreeStats.Skipped))
+			}
+		}()
+	}
+	for i := range frontier {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	stats.Searched += int(searched.Load())
+	stats.Skipped += int(skipped.Load())
+
+	// Pick the first of the best results, so the answer doesn't depend on
+	// which worker finished first.
+	for _, s := range results {
+		if s.Flow > best.Flow {
+			best = s
+		}
+	}
+	return ScheduleFromState(best, config.NumAgents), stats
+}
+
+// Does a depth first search of every state below root, returning the best
+// one. Subtrees that can't beat the shared best flow are skipped, and the
+// shared best flow is raised whenever this search beats it.
+func searchSubtree(g *Graph, root SearchState, config Config, bound Bound, sharedBest *atomic.Int64) (SearchState, SearchStats) {
+	stats := SearchStats{}
+	best := root
+	raiseSharedBest(sharedBest, best.Flow)
+
+	toVisit := []SearchState{root}
+	for len(toVisit) > 0 {
+		// Pop the last node
+		currentState := toVisit[len(toVisit)-1]
+		toVisit = toVisit[:len(toVisit)-1]
+
+		// Only replace our own best if it's strictly better, so that the
+		// first best state in search order is the one that's kept.
+		if currentState.Flow > best.Flow {
+			best = currentState
+			raiseSharedBest(sharedBest, best.Flow)
+		}
+
+		if bound.UpperBound(g, currentState, config) < int(sharedBest.Load()) {
+			stats.Skipped++
+			continue
+		}
+
+		toVisit = append(toVisit, currentState.GetSubStates(g, config)...)
+		stats.Searched++
+	}
+	return best, stats
+}
+
+func raiseSharedBest(sharedBest *atomic.Int64, flow int) {
+	for {
+		current := sharedBest.Load()
+		if int64(flow) <= current || sharedBest.CompareAndSwap(current, int64(flow)) {
+			return
+		}
+	}
+}
@@ -0,0 +1,217 @@
+package valves
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var nodeRegexp = regexp.MustCompile(`^Valve (\w+) has flow rate=(\d+); tunnels? leads? to valves? (.+)$`)
+
+// One destination in the list of tunnels, optionally followed by its cost and
+// when it's open in brackets, like "BB (cost 2, open 10-20)".
+var destRegexp = regexp.MustCompile(`^(\w+)(?: \(([^)]*)\))?(, |$)`)
+
+var attrRegexp = regexp.MustCompile(`^(?:cost (\d+)|open (\d+)-(\d+)|open from (\d+)|open until (\d+))$`)
+
+// A problem with one line of the input.
+type ParseError struct {
+	// Line number, counting from 1.
+	Line   int
+	Reason string
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("line %d: %s", e.Line, e.Reason)
+}
+
+// Every problem found in an input, in line order.
+type ParseErrors []*ParseError
+
+func (e ParseErrors) Error() string {
+	messages := make([]string, len(e))
+	for i, err := range e {
+		messages[i] = err.Error()
+	}
+	return strings.Join(messages, "\n")
+}
+
+// Parse an example string like this:
+// Valve GJ has flow rate=14; tunnels lead to valves UV, AO, MM, UD, GM
+//
+// Each tunnel can also have a cost (the default is 1) and any number of
+// windows when it's open, in minutes since the start, like this:
+// Valve GJ has flow rate=14; tunnels lead to valves UV (cost 3), AO (open from 10), MM (cost 2, open 5-8, open until 20)
+func ParseNode(s string) (*Node, error) {
+	matches := nodeRegexp.FindStringSubmatch(strings.TrimSpace(s))
+	if matches == nil {
+		return nil, fmt.Errorf("invalid valve description: %q", s)
+	}
+
+	// Create the node
+	value, err := strconv.Atoi(matches[2])
+	if err != nil {
+		return nil, fmt.Errorf("invalid flow rate %q: %w", matches[2], err)
+	}
+	node := &Node{
+		Name:  matches[1],
+		Value: value,
+		Edges: make(map[string]*Edge),
+	}
+	// Create the edges
+	rest := matches[3]
+	for rest != "" {
+		destMatches := destRegexp.FindStringSubmatch(rest)
+		if destMatches == nil || (destMatches[3] == ", " && len(destMatches[0]) == len(rest)) {
+			return nil, fmt.Errorf("invalid tunnel list at %q", rest)
+		}
+		rest = rest[len(destMatches[0]):]
+
+		destName := destMatches[1]
+		if destName == node.Name {
+			return nil, fmt.Errorf("tunnel from %s leads back to itself", node.Name)
+		}
+		if _, ok := node.Edges[destName]; ok {
+			return nil, fmt.Errorf("tunnel from %s to %s is listed twice", node.Name, destName)
+		}
+		edge, err := parseEdge(destName, destMatches[2])
+		if err != nil {
+			return nil, fmt.Errorf("tunnel from %s to %s: %w", node.Name, destName, err)
+		}
+		node.Edges[destName] = edge
+	}
+	return node, nil
+}
+
+// Parses the part of a tunnel in brackets, like "cost 2, open 10-20".
+func parseEdge(destName string, attrs string) (*Edge, error) {
+	edge := &Edge{
+		DestName: destName,
+		Cost:     1,
+	}
+	if attrs == "" {
+		return edge, nil
+	}
+	for _, attr := range strings.Split(attrs, ", ") {
+		m := attrRegexp.FindStringSubmatch(attr)
+		if m == nil {
+			return nil, fmt.Errorf("invalid tunnel attribute %q", attr)
+		}
+		nums := make([]int, len(m))
+		for i := 1; i < len(m); i++ {
+			if m[i] == "" {
+				continue
+			}
+			n, err := strconv.Atoi(m[i])
+			if err != nil || n >= NoEnd {
+				return nil, fmt.Errorf("invalid number %q", m[i])
+			}
+			nums[i] = n
+		}
+		switch {
+		case m[1] != "":
+			if nums[1] < 1 {
+				return nil, fmt.Errorf("cost must be at least 1")
+			}
+			edge.Cost = nums[1]
+		case m[2] != "":
+			if nums[2] >= nums[3] {
+				return nil, fmt.Errorf("window %q closes before it opens", attr)
+			}
+			edge.Windows = append(edge.Windows, Window{From: nums[2], Until: nums[3]})
+		case m[4] != "":
+			edge.Windows = append(edge.Windows, Window{From: nums[4], Until: NoEnd})
+		case m[5] != "":
+			if nums[5] < 1 {
+				return nil, fmt.Errorf("window %q is never open", attr)
+			}
+			edge.Windows = append(edge.Windows, Window{From: 0, Until: nums[5]})
+		}
+	}
+	sort.Slice(edge.Windows, func(i, j int) bool {
+		return edge.Windows[i].From < edge.Windows[j].From
+	})
+	return edge, nil
+}
+
+// Reads a graph with one valve per line, skipping blank lines. Every problem
+// is reported with its line number, as ParseErrors, including tunnels to
+// valves that don't exist and tunnels that only go one way.
+func ParseGraph(r io.Reader) (*Graph, error) {
+	graph := &Graph{
+		Nodes: make(map[string]*Node),
+	}
+	errs := ParseErrors{}
+	// Which line each node came from, for reporting problems with its edges.
+	lines := make(map[string]int)
+
+	scanner := bufio.NewScanner(r)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := scanner.Text()
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		node, err := ParseNode(line)
+		if err != nil {
+			errs = append(errs, &ParseError{Line: lineNum, Reason: err.Error()})
+			continue
+		}
+		if prevLine, ok := lines[node.Name]; ok {
+			errs = append(errs, &ParseError{
+				Line:   lineNum,
+				Reason: fmt.Sprintf("valve %s was already described on line %d", node.Name, prevLine),
+			})
+			continue
+		}
+		graph.Nodes[node.Name] = node
+		lines[node.Name] = lineNum
+	}
+	if err := scanner.Err(); err != nil {
+		errs = append(errs, &ParseError{Line: lineNum + 1, Reason: err.Error()})
+	}
+
+	// Check the edges, now that we know all the nodes.
+	for name, node := range graph.Nodes {
+		for _, destName := range node.SortedEdgeNames() {
+			dest, ok := graph.Nodes[destName]
+			if !ok {
+				errs = append(errs, &ParseError{
+					Line:   lines[name],
+					Reason: fmt.Sprintf("tunnel from %s leads to unknown valve %s", name, destName),
+				})
+				continue
+			}
+			back, ok := dest.Edges[name]
+			if !ok {
+				errs = append(errs, &ParseError{
+					Line:   lines[name],
+					Reason: fmt.Sprintf("tunnel from %s to %s has no tunnel back on line %d", name, destName, lines[destName]),
+				})
+				continue
+			}
+			// Only report mismatches once, from the first of the pair.
+			edge := node.Edges[destName]
+			if name < destName && (edge.Cost != back.Cost || !reflect.DeepEqual(edge.Windows, back.Windows)) {
+				errs = append(errs, &ParseError{
+					Line:   lines[name],
+					Reason: fmt.Sprintf("tunnel from %s to %s doesn't match the tunnel back on line %d", name, destName, lines[destName]),
+				})
+			}
+		}
+	}
+
+	if len(errs) > 0 {
+		sort.SliceStable(errs, func(i, j int) bool {
+			return errs[i].Line < errs[j].Line
+		})
+		return nil, errs
+	}
+	return graph, nil
+}
@@ -0,0 +1,82 @@
+package valves
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseGraph(t *testing.T) {
+	input := `Valve AA has flow rate=0; tunnels lead to valves DD, BB
+Valve BB has flow rate=13; tunnel leads to valve AA
+
+Valve DD has flow rate=20; tunnel leads to valve AA
+`
+	g, err := ParseGraph(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(g.Nodes) != 3 {
+		t.Fatalf("Expected 3 nodes, got %d", len(g.Nodes))
+	}
+	if g.Nodes["DD"].Value != 20 {
+		t.Errorf("Expected DD to have flow rate 20, got %d", g.Nodes["DD"].Value)
+	}
+	if !reflect.DeepEqual(g.Nodes["AA"].SortedEdgeNames(), []string{"BB", "DD"}) {
+		t.Errorf("Expected AA to lead to BB and DD, got %v", g.Nodes["AA"].SortedEdgeNames())
+	}
+}
+
+func TestParseGraphErrors(t *testing.T) {
+	cases := []struct {
+		name  string
+		input string
+		lines []int
+	}{
+		{
+			name:  "malformed line",
+			input: "Valve AA has flow rate=0; tunnel leads to valve BB\nValve BB has flow rate=x; tunnel leads to valve AA\n",
+			// Line 1 also fails because BB never gets declared.
+			lines: []int{1, 2},
+		},
+		{
+			name:  "unknown valve",
+			input: "Valve AA has flow rate=0; tunnels lead to valves BB, CC\nValve BB has flow rate=1; tunnel leads to valve AA\n",
+			lines: []int{1},
+		},
+		{
+			name:  "one way tunnel",
+			input: "Valve AA has flow rate=0; tunnel leads to valve BB\nValve BB has flow rate=1; tunnel leads to valve CC\nValve CC has flow rate=1; tunnel leads to valve BB\n",
+			lines: []int{1},
+		},
+		{
+			name:  "duplicate valve",
+			input: "Valve AA has flow rate=0; tunnel leads to valve BB\nValve BB has flow rate=1; tunnel leads to valve AA\nValve AA has flow rate=2; tunnel leads to valve BB\n",
+			lines: []int{3},
+		},
+		{
+			name:  "tunnel to itself",
+			input: "Valve AA has flow rate=0; tunnels lead to valves AA, BB\nValve BB has flow rate=1; tunnel leads to valve AA\n",
+			// AA is rejected, so BB's tunnel back to it fails too.
+			lines: []int{1, 2},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			_, err := ParseGraph(strings.NewReader(c.input))
+			var parseErrs ParseErrors
+			if !errors.As(err, &parseErrs) {
+				t.Fatalf("Expected ParseErrors, got %v", err)
+			}
+			lines := make([]int, len(parseErrs))
+			for i, e := range parseErrs {
+				lines[i] = e.Line
+			}
+			if !reflect.DeepEqual(lines, c.lines) {
+				t.Errorf("Expected errors on lines %v, got %v", c.lines, err)
+			}
+		})
+	}
+}
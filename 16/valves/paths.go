@@ -0,0 +1,138 @@
+package valves
+
+import (
+	"sort"
+
+	"advent2022/common/pq"
+)
+
+// The shortest distance from one node to every node that can be reached from
+// it, along with the node before each one on a shortest path, so the actual
+// route can be followed.
+type ShortestPathTree struct {
+	From string
+	Dist map[string]int
+	// The node before each node on the shortest path from From. From itself
+	// has no previous node.
+	Prev map[string]string
+}
+
+// Gets the nodes on the shortest path to a node, including both ends. Returns
+// false if the node can't be reached.
+func (t ShortestPathTree) PathTo(to string) ([]string, bool) {
+	if _, ok := t.Dist[to]; !ok {
+		return nil, false
+	}
+	path := []string{to}
+	for to != t.From {
+		to = t.Prev[to]
+		path = append(path, to)
+	}
+	// Reverse it so it starts at From.
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+	return path, true
+}
+
+// A node in Dijkstra's algorithm, with the time it's reached.
+type NodeDistPair struct {
+	Node *Node
+	Dist int
+	// The node we came from to get to this node.
+	Prev string
+}
+
+func nodeDistLess(a, b NodeDistPair) bool {
+	if a.Dist != b.Dist {
+		return a.Dist < b.Dist
+	}
+	// Break ties by name so the search order doesn't depend on the heap.
+	if a.Node.Name != b.Node.Name {
+		return a.Node.Name < b.Node.Name
+	}
+	return a.Prev < b.Prev
+}
+
+// Uses Dijkstra's algorithm to find the shortest paths from a node to every
+// other node, ignoring when the tunnels are open. Ties are broken by node name,
+// so the paths are always the same for the same graph.
+func (g *Graph) ShortestPaths(from string) ShortestPathTree {
+	return g.shortestPaths(from, 0, false)
+}
+
+// Same as ShortestPaths, but for setting off after departure minutes, so only
+// tunnels that are open by the time they're reached can be used. The
+// distances are travel times, including any time spent waiting for a tunnel to
+// open.
+func (g *Graph) ShortestPathsAt(from string, departure int) ShortestPathTree {
+	return g.shortestPaths(from, departure, true)
+}
+
+func (g *Graph) shortestPaths(from string, departure int, useWindows bool) ShortestPathTree {
+	tree := ShortestPathTree{
+		From: from,
+		Dist: make(map[string]int),
+		Prev: make(map[string]string),
+	}
+	node, ok := g.Nodes[from]
+	if !ok {
+		return tree
+	}
+
+	// The distances in the queue are the times each node is reached. Waiting
+	// is allowed, so getting somewhere sooner never makes it later to go on
+	// from there, which is what Dijkstra's algorithm needs.
+	toVisit := pq.NewIndexed[string](nodeDistLess)
+	toVisit.Push(from, NodeDistPair{Node: node, Dist: departure})
+
+	for toVisit.Len() > 0 {
+		_, current := toVisit.Pop()
+
+		tree.Dist[current.Node.Name] = current.Dist - departure
+		if current.Node != node {
+			tree.Prev[current.Node.Name] = current.Prev
+		}
+
+		// Add the edges to the list of nodes to visit, or update them if
+		// this is a shorter way to get to them.
+		for _, destName := range current.Node.SortedEdgeNames() {
+			if _, ok := tree.Dist[destName]; ok {
+				continue
+			}
+			edge := current.Node.Edges[destName]
+			leave := current.Dist
+			if useWindows {
+				leave, ok = edge.NextOpen(leave)
+				if !ok {
+					continue
+				}
+			}
+			toVisit.DecreaseKey(destName, NodeDistPair{
+				Node: g.Nodes[destName],
+				Dist: leave + edge.Cost,
+				Prev: current.Node.Name,
+			})
+		}
+	}
+	return tree
+}
+
+// Finds the shortest paths from every node, keyed by the starting node.
+func (g *Graph) AllPairsShortestPaths() map[string]ShortestPathTree {
+	trees := make(map[string]ShortestPathTree)
+	for name := range g.Nodes {
+		trees[name] = g.ShortestPaths(name)
+	}
+	return trees
+}
+
+// The names of the nodes this node has edges to, in order.
+func (n *Node) SortedEdgeNames() []string {
+	names := make([]string, 0, len(n.Edges))
+	for name := range n.Edges {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
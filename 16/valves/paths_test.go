@@ -0,0 +1,152 @@
+package valves
+
+import (
+	"reflect"
+	"testing"
+)
+
+func graphFromLines(lines []string) *Graph {
+	g := &Graph{
+		Nodes: make(map[string]*Node),
+	}
+	for _, line := range lines {
+		node := NodeFromString(line)
+		g.Nodes[node.Name] = node
+	}
+	return g
+}
+
+// A square with a tail, where the direct tunnel AA-DD is slower than going
+// around through BB and CC:
+//
+//	AA --1-- BB
+//	|        |
+//	5        1
+//	|        |
+//	DD --1-- CC --2-- EE
+//
+// FF and GG are only connected to each other.
+func costedGraph() *Graph {
+	g := graphFromLines([]string{
+		"Valve AA has flow rate=0; tunnels lead to valves BB, DD",
+		"Valve BB has flow rate=3; tunnels lead to valves AA, CC",
+		"Valve CC has flow rate=0; tunnels lead to valves BB, DD, EE",
+		"Valve DD has flow rate=7; tunnels lead to valves AA, CC",
+		"Valve EE has flow rate=2; tunnel leads to valve CC",
+		"Valve FF has flow rate=9; tunnel leads to valve GG",
+		"Valve GG has flow rate=0; tunnel leads to valve FF",
+	})
+	g.Nodes["AA"].Edges["DD"].Cost = 5
+	g.Nodes["DD"].Edges["AA"].Cost = 5
+	g.Nodes["CC"].Edges["EE"].Cost = 2
+	g.Nodes["EE"].Edges["CC"].Cost = 2
+	return g
+}
+
+func TestShortestPaths(t *testing.T) {
+	cases := []struct {
+		name  string
+		graph *Graph
+		from  string
+		dist  map[string]int
+		paths map[string][]string
+	}{
+		{
+			name: "line",
+			graph: graphFromLines([]string{
+				"Valve AA has flow rate=0; tunnel leads to valve BB",
+				"Valve BB has flow rate=1; tunnels lead to valves AA, CC",
+				"Valve CC has flow rate=2; tunnel leads to valve BB",
+			}),
+			from: "AA",
+			dist: map[string]int{"AA": 0, "BB": 1, "CC": 2},
+			paths: map[string][]string{
+				"AA": {"AA"},
+				"CC": {"AA", "BB", "CC"},
+			},
+		},
+		{
+			name:  "cheaper way round",
+			graph: costedGraph(),
+			from:  "AA",
+			dist:  map[string]int{"AA": 0, "BB": 1, "CC": 2, "DD": 3, "EE": 4},
+			paths: map[string][]string{
+				"DD": {"AA", "BB", "CC", "DD"},
+				"EE": {"AA", "BB", "CC", "EE"},
+			},
+		},
+		{
+			name:  "from the middle",
+			graph: costedGraph(),
+			from:  "EE",
+			dist:  map[string]int{"AA": 4, "BB": 3, "CC": 2, "DD": 3, "EE": 0},
+			paths: map[string][]string{
+				"AA": {"EE", "CC", "BB", "AA"},
+				"DD": {"EE", "CC", "DD"},
+			},
+		},
+		{
+			name:  "unconnected",
+			graph: costedGraph(),
+			from:  "FF",
+			dist:  map[string]int{"FF": 0, "GG": 1},
+			paths: map[string][]string{
+				"GG": {"FF", "GG"},
+				"AA": nil,
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			tree := c.graph.ShortestPaths(c.from)
+			if !reflect.DeepEqual(tree.Dist, c.dist) {
+				t.Errorf("Expected distances %v, got %v", c.dist, tree.Dist)
+			}
+			for to, expected := range c.paths {
+				path, ok := tree.PathTo(to)
+				if ok != (expected != nil) {
+					t.Errorf("Expected reachable(%s) = %v, got %v", to, expected != nil, ok)
+				}
+				if !reflect.DeepEqual(path, expected) {
+					t.Errorf("Expected path to %s to be %v, got %v", to, expected, path)
+				}
+			}
+		})
+	}
+}
+
+func TestAllPairsShortestPaths(t *testing.T) {
+	g := costedGraph()
+	trees := g.AllPairsShortestPaths()
+	if len(trees) != len(g.Nodes) {
+		t.Fatalf("Expected %d trees, got %d", len(g.Nodes), len(trees))
+	}
+	// The graph is undirected, so the distances should be symmetric.
+	for from, tree := range trees {
+		for to, dist := range tree.Dist {
+			if back := trees[to].Dist[from]; back != dist {
+				t.Errorf("Distance %s to %s is %d, but back is %d", from, to, dist, back)
+			}
+		}
+	}
+}
+
+func TestCalculateDistancesToEachNode(t *testing.T) {
+	g := costedGraph()
+	g.CalculateDistancesToEachNode()
+
+	expected := map[[2]string]int{
+		{"AA", "DD"}: 3,
+		{"DD", "AA"}: 3,
+		{"BB", "EE"}: 3,
+		{"AA", "AA"}: 0,
+		{"AA", "FF"}: Unreachable,
+	}
+	for pair, dist := range expected {
+		got := g.Distances.Get(g.Indices[pair[0]], g.Indices[pair[1]])
+		if got != dist {
+			t.Errorf("Expected distance %s to %s to be %d, got %d", pair[0], pair[1], dist, got)
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package valves
+
+import (
+	"fmt"
+	"strings"
+)
+
+// A single valve being opened by one of the agents. Minutes count from 1, like
+// the puzzle does, so an agent that walks one tunnel and takes a minute to open
+// the valve there arrives in minute 1 and opens it in minute 2.
+type ValveOpening struct {
+	Agent int    `json:"agent"`
+	Valve string `json:"valve"`
+	// The minute in which the agent finishes walking to the valve.
+	ArrivedMinute int `json:"arrivedMinute"`
+	// The last minute the agent spends opening the valve. It releases
+	// pressure from the end of this minute onwards.
+	OpenedMinute int `json:"openedMinute"`
+	// Total pressure released by this valve until the time runs out.
+	Pressure int `json:"pressure"`
+}
+
+// Linked list of the valves opened in a search, most recent first.
+type OpeningHistory struct {
+	Opening ValveOpening
+	Prev    *OpeningHistory
+}
+
+// The valves each agent opens, in the order they open them.
+type Schedule struct {
+	TotalPressure int              `json:"totalPressure"`
+	Agents        [][]ValveOpening `json:"agents"`
+}
+
+func ScheduleFromState(s SearchState, numAgents int) Schedule {
+	schedule := Schedule{
+		TotalPressure: s.Flow,
+		Agents:        make([][]ValveOpening, numAgents),
+	}
+	for i := range schedule.Agents {
+		schedule.Agents[i] = make([]ValveOpening, 0)
+	}
+	// Walk back through the history, then reverse so each agent's valves are
+	// in order.
+	for h := s.LastOpened; h != nil; h = h.Prev {
+		agent := h.Opening.Agent
+		schedule.Agents[agent] = append(schedule.Agents[agent], h.Opening)
+	}
+	for _, openings := range schedule.Agents {
+		for i, j := 0, len(openings)-1; i < j; i, j = i+1, j-1 {
+			openings[i], openings[j] = openings[j], openings[i]
+		}
+	}
+	return schedule
+}
+
+// Prints the timeline for each agent, like this:
+//
+//	Agent 1:
+//	  Minute  1: arrive at DD, minute  2: open DD (releases 560)
+func (s Schedule) String() string {
+	var sb strings.Builder
+	for agent, openings := range s.Agents {
+		sb.WriteString(fmt.Sprintf("Agent %d:\n", agent+1))
+		if len(openings) == 0 {
+			sb.WriteString("  Nothing to open\n")
+		}
+		for _, o := range openings {
+			sb.WriteString(fmt.Sprintf("  Minute %2d: arrive at %s, minute %2d: open %s (releases %d)\n",
+				o.ArrivedMinute, o.Valve, o.OpenedMinute, o.Valve, o.Pressure))
+		}
+	}
+	sb.WriteString(fmt.Sprintf("Total pressure released: %d", s.TotalPressure))
+	return sb.String()
+}
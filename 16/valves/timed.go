@@ -0,0 +1,125 @@
+package valves
+
+import (
+	"fmt"
+	"math"
+)
+
+// Used as the end of a window that never closes.
+const NoEnd = math.MaxInt32
+
+// A span of time when a tunnel can be entered, in minutes since the start. An
+// agent that's waiting to use a tunnel can enter it once From minutes have
+// passed, up until (but not including) Until.
+type Window struct {
+	From  int
+	Until int
+}
+
+func (w Window) String() string {
+	if w.Until == NoEnd {
+		return fmt.Sprintf("from %d", w.From)
+	}
+	if w.From == 0 {
+		return fmt.Sprintf("until %d", w.Until)
+	}
+	return fmt.Sprintf("%d-%d", w.From, w.Until)
+}
+
+// The cost of the tunnel, and when it's open if it isn't always open.
+func (e *Edge) Label() string {
+	label := fmt.Sprintf("%d", e.Cost)
+	for _, w := range e.Windows {
+		label += fmt.Sprintf(", open %s", w)
+	}
+	return label
+}
+
+// Gets the earliest time, no earlier than t, that the tunnel can be entered.
+// Returns false if the tunnel never opens again.
+func (e *Edge) NextOpen(t int) (int, bool) {
+	if len(e.Windows) == 0 {
+		return t, true
+	}
+	// The windows are sorted, so the first one that's still open is the
+	// soonest.
+	for _, w := range e.Windows {
+		if t < w.Until {
+			return intMax(t, w.From), true
+		}
+	}
+	return 0, false
+}
+
+// Whether any tunnels in the graph are only open some of the time.
+func (g *Graph) HasWindows() bool {
+	for _, node := range g.Nodes {
+		for _, edge := range node.Edges {
+			if len(edge.Windows) > 0 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Whether a node can be removed by joining up its neighbours, which only
+// works if the tunnels to it and between its neighbours are always open.
+func (g *Graph) canDeleteNode(node *Node) bool {
+	for _, edge := range node.Edges {
+		if len(edge.Windows) > 0 {
+			return false
+		}
+		for _, other := range node.Edges {
+			if between, ok := g.Nodes[edge.DestName].Edges[other.DestName]; ok && len(between.Windows) > 0 {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// Travel times between every pair of nodes, for each minute an agent could set
+// off at. Waiting for tunnels to open is included in the travel time.
+type TimedDistances struct {
+	// The last departure minute that's stored.
+	Horizon int
+	// One matrix for each departure minute from 0 to Horizon.
+	ByTime []DistanceMatrix
+}
+
+// Works out the travel times for every departure minute up to horizon. This
+// has to be called after CalculateDistancesToEachNode, so that the nodes have
+// their indices.
+func (g *Graph) CalculateTimedDistances(horizon int) {
+	timed := &TimedDistances{
+		Horizon: horizon,
+		ByTime:  make([]DistanceMatrix, horizon+1),
+	}
+	for t := 0; t <= horizon; t++ {
+		distances := NewDistanceMatrix(len(g.Names))
+		for from, fromName := range g.Names {
+			for toName, dist := range g.ShortestPathsAt(fromName, t).Dist {
+				distances.Set(from, g.Indices[toName], dist)
+			}
+		}
+		timed.ByTime[t] = distances
+	}
+	g.Timed = timed
+}
+
+// How long it takes to get from one node to another, setting off after
+// elapsed minutes.
+func (g *Graph) TravelTime(from, to, elapsed int) int {
+	if g.Timed == nil {
+		return g.Distances.Get(from, to)
+	}
+	return g.Timed.ByTime[elapsed].Get(from, to)
+}
+
+func intMax(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
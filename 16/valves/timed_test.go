@@ -0,0 +1,127 @@
+package valves
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseNodeWithCostsAndWindows(t *testing.T) {
+	node, err := ParseNode("Valve AA has flow rate=3; tunnels lead to valves BB (cost 2), CC (open 5-8, open until 3), DD (cost 4, open from 10), EE")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := map[string]*Edge{
+		"BB": {DestName: "BB", Cost: 2},
+		"CC": {DestName: "CC", Cost: 1, Windows: []Window{{From: 0, Until: 3}, {From: 5, Until: 8}}},
+		"DD": {DestName: "DD", Cost: 4, Windows: []Window{{From: 10, Until: NoEnd}}},
+		"EE": {DestName: "EE", Cost: 1},
+	}
+	if !reflect.DeepEqual(node.Edges, expected) {
+		for name, edge := range node.Edges {
+			t.Logf("%s: %+v", name, *edge)
+		}
+		t.Errorf("Edges didn't match")
+	}
+
+	for _, bad := range []string{
+		"Valve AA has flow rate=3; tunnel leads to valve BB (cost 0)",
+		"Valve AA has flow rate=3; tunnel leads to valve BB (open 8-5)",
+		"Valve AA has flow rate=3; tunnel leads to valve BB (closed)",
+		"Valve AA has flow rate=3; tunnels lead to valves BB, ",
+	} {
+		if _, err := ParseNode(bad); err == nil {
+			t.Errorf("Expected an error for %q", bad)
+		}
+	}
+}
+
+// The direct tunnel from AA to BB only opens after 5 minutes, and the way
+// round through CC takes 8 minutes.
+const timedInput = `Valve AA has flow rate=0; tunnels lead to valves BB (open from 5), CC (cost 4)
+Valve BB has flow rate=10; tunnels lead to valves AA (open from 5), CC (cost 4)
+Valve CC has flow rate=0; tunnels lead to valves AA (cost 4), BB (cost 4)
+`
+
+func timedGraph(t *testing.T, config Config) *Graph {
+	g, err := ParseGraph(strings.NewReader(timedInput))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	g.RemoveZeroValueNodes(config.StartNode)
+	g.CalculateDistancesToEachNode()
+	g.CalculateTimedDistances(config.AgentMinutes)
+	return g
+}
+
+func TestTimedDistances(t *testing.T) {
+	config := Config{StartNode: "AA", AgentMinutes: 10, OpenTime: 1, NumAgents: 1}
+	g := timedGraph(t, config)
+
+	// CC can't be removed, because joining AA and BB through it would lose
+	// the window on the direct tunnel.
+	if _, ok := g.Nodes["CC"]; !ok {
+		t.Fatalf("Expected CC to be kept")
+	}
+
+	aa, bb := g.Indices["AA"], g.Indices["BB"]
+	cases := []struct {
+		elapsed int
+		dist    int
+	}{
+		// Waiting for the tunnel to open is quicker than going round.
+		{0, 6},
+		{2, 4},
+		{5, 1},
+		{9, 1},
+	}
+	for _, c := range cases {
+		if got := g.TravelTime(aa, bb, c.elapsed); got != c.dist {
+			t.Errorf("Expected travel time at %d to be %d, got %d", c.elapsed, c.dist, got)
+		}
+	}
+	// Ignoring the windows gives a lower bound.
+	if got := g.Distances.Get(aa, bb); got != 1 {
+		t.Errorf("Expected distance ignoring windows to be 1, got %d", got)
+	}
+
+	path, _ := g.ShortestPathsAt("AA", 0).PathTo("BB")
+	if !reflect.DeepEqual(path, []string{"AA", "BB"}) {
+		t.Errorf("Expected to wait for the direct tunnel, got %v", path)
+	}
+}
+
+func TestSolveWithWindows(t *testing.T) {
+	config := Config{StartNode: "AA", AgentMinutes: 10, OpenTime: 1, NumAgents: 1}
+	g := timedGraph(t, config)
+
+	// Get to BB in minute 6, open it in minute 7, and it's open for 3 minutes.
+	expected := 30
+	for name, schedule := range map[string]Schedule{
+		"branch and bound": Solve(g, config),
+		"dp":               SolveDP(g, config),
+	} {
+		if schedule.TotalPressure != expected {
+			t.Errorf("%s: expected %d, got %d", name, expected, schedule.TotalPressure)
+		}
+		opening := schedule.Agents[0][0]
+		if opening.ArrivedMinute != 6 || opening.OpenedMinute != 7 {
+			t.Errorf("%s: expected to arrive in minute 6 and open in minute 7, got %+v", name, opening)
+		}
+	}
+}
+
+// The travel times are worked out for each minute, so a negative number of
+// minutes has to be caught before that.
+func TestGraphFromFileNegativeMinutes(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "input.txt")
+	if err := os.WriteFile(filename, []byte(timedInput), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	config := Config{StartNode: "AA", AgentMinutes: -3, OpenTime: 1, NumAgents: 1}
+	if _, err := GraphFromFile(filename, config); err == nil {
+		t.Errorf("Expected an error")
+	}
+}
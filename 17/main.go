@@ -0,0 +1,65 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+
+	"advent2022/17/tetris"
+)
+
+// Prints the height of the tower after each number of pieces, e.g.
+//
+//	go run ./17 17/input.txt 2022 1000000000000
+func main() {
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s <input file> [pieces...]\n", os.Args[0])
+		fmt.Fprintln(flag.CommandLine.Output(), "With no piece counts, gives the answers for 2022 and 1000000000000 pieces.")
+	}
+	flag.Parse()
+	if flag.NArg() < 1 {
+		flag.Usage()
+		os.Exit(1)
+	}
+
+	pieceCounts := []int64{2022, 1000000000000}
+	if flag.NArg() > 1 {
+		pieceCounts = pieceCounts[:0]
+		for _, arg := range flag.Args()[1:] {
+			n, err := strconv.ParseInt(arg, 10, 64)
+			if err != nil || n < 0 {
+				fmt.Fprintf(os.Stderr, "Invalid piece count %q\n", arg)
+				os.Exit(1)
+			}
+			pieceCounts = append(pieceCounts, n)
+		}
+	}
+
+	if err := solve(flag.Arg(0), pieceCounts); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func solve(filename string, pieceCounts []int64) error {
+	jets, err := tetris.ReadJetsFile(filename)
+	if err != nil {
+		return err
+	}
+	fmt.Println("num moves:", len(jets))
+
+	// Find a loop, and use it to skip ahead.
+	cycle, err := tetris.NewSimulator(jets).FindCycle(tetris.MaxCyclePieces)
+	if err != nil {
+		return err
+	}
+	fmt.Println("cycle starts after", cycle.Start, "pieces")
+	fmt.Println("pieces diff", cycle.Length)
+	fmt.Println("height diff", cycle.HeightGain)
+
+	for _, n := range pieceCounts {
+		fmt.Printf("height after %d pieces: %d\n", n, cycle.HeightAfter(n))
+	}
+	return nil
+}
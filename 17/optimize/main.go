@@ -0,0 +1,77 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"advent2022/17/tetris"
+)
+
+// Finds the moves that give the shortest tower (or the most filled rows), e.g.
+//
+//	go run ./17/optimize -pieces 50 -moves 3
+//	go run ./17/optimize -objective rows -width 10 -shapes pentominoes.txt
+func main() {
+	pieces := flag.Int("pieces", 100, "how many pieces to drop")
+	moves := flag.Int("moves", 3, "how many times each piece can be pushed")
+	beam := flag.Int("beam", 100, "how many boards to keep after each piece")
+	objective := flag.String("objective", "height", "what to optimize: height (minimize) or rows (maximize completed rows)")
+	width := flag.Int("width", tetris.DefaultConfig.Width, "width of the chamber")
+	shapesFile := flag.String("shapes", "", "file with custom piece shapes, drawn with '#' and '.' and separated by blank lines")
+	spawnX := flag.Int("spawn-x", tetris.DefaultConfig.SpawnX, "how far from the left wall new pieces appear")
+	spawnY := flag.Int("spawn-y", tetris.DefaultConfig.SpawnY, "how far above the tower new pieces appear")
+	flag.Parse()
+
+	opts := tetris.OptimizeOptions{
+		Config: tetris.Config{
+			Width:  *width,
+			Shapes: tetris.PieceShapes,
+			SpawnX: *spawnX,
+			SpawnY: *spawnY,
+		},
+		Pieces:        *pieces,
+		MovesPerPiece: *moves,
+		BeamWidth:     *beam,
+	}
+	switch *objective {
+	case "height":
+		opts.Objective = tetris.MinimizeHeight
+	case "rows":
+		opts.Objective = tetris.MaximizeRows
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown objective %q, expected height or rows\n", *objective)
+		os.Exit(1)
+	}
+
+	if err := optimize(opts, *shapesFile); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func optimize(opts tetris.OptimizeOptions, shapesFile string) error {
+	if shapesFile != "" {
+		input, err := os.ReadFile(shapesFile)
+		if err != nil {
+			return err
+		}
+		opts.Config.Shapes, err = tetris.ParseShapes(string(input))
+		if err != nil {
+			return fmt.Errorf("%s: %w", shapesFile, err)
+		}
+	}
+
+	plan, err := tetris.Optimize(opts)
+	if err != nil {
+		return err
+	}
+
+	for i, moves := range plan.Moves {
+		fmt.Printf("Piece %4d: %s\n", i+1, moves)
+	}
+	fmt.Println(plan.Board)
+	fmt.Println("height", plan.Height)
+	fmt.Println("completed rows", plan.CompletedRows)
+	return nil
+}
@@ -0,0 +1,295 @@
+package tetris
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Chambers can be at most this wide, as each row is stored in a uint64.
+const MaxWidth = 64
+
+// These look flipped compared to the actual shapes, which is because of the bit
+// representation we're using for the board/pieces.
+var PieceShapes = [][]uint64{
+	// Horizontal line
+	{0b1111},
+	// Plus shape
+	{0b010, 0b111, 0b010},
+	// L shape
+	{0b111, 0b100, 0b100},
+	// Vertical line
+	{0b1, 0b1, 0b1, 0b1},
+	// Block
+	{0b11, 0b11},
+}
+
+type Piece struct {
+	// A bit representation of this piece, with the bottom row first.
+	Shape []uint64
+	// Position of the bottom left corner of this piece.
+	X      int
+	Y      int
+	Width  int
+	Height int
+}
+
+// Gets the value of the piece at the position relative to the bottom left corner.
+// False if the position is out of bounds.
+func (p Piece) Get(x, y int) bool {
+	if x < 0 || x >= p.Width || y < 0 || y >= p.Height {
+		return false
+	}
+	return p.Shape[y]&(1<<x) != 0
+}
+
+func NewPiece(shape []uint64) Piece {
+	// Find the maximum width of any row.
+	width := 0
+	for _, row := range shape {
+		rowWidth := 0
+		for i := 0; i < MaxWidth; i++ {
+			if row&(1<<i) != 0 {
+				rowWidth = i + 1
+			}
+		}
+		if rowWidth > width {
+			width = rowWidth
+		}
+	}
+	return Piece{
+		Shape:  shape,
+		Width:  width,
+		Height: len(shape),
+	}
+}
+
+// Represents a 2D board as bits in an array of rows.
+// The first row is the bottom of the board, and each row is higher up.
+// The lowest bit of each row is the leftmost column of the board.
+// Rows that pieces can't reach any more can be dropped with Prune, so the
+// first row isn't always y = 0.
+type Board struct {
+	Width int
+	Rows  []uint64
+	// How many rows have been pruned from the bottom of the board. Rows[0] is
+	// at y = Floor.
+	Floor int
+}
+
+func NewBoard(width int) Board {
+	return Board{
+		Width: width,
+		// Start with some arbitrary height.
+		Rows: make([]uint64, 0, 10),
+	}
+}
+
+// A row with every column filled.
+func (b Board) fullRow() uint64 {
+	return ^uint64(0) >> (MaxWidth - b.Width)
+}
+
+// Gets the value of the board at the position.
+// If x is out of bounds, treat it as a wall, return true.
+// If y is too low, treat it as the floor and return true. This includes rows
+// that have been pruned.
+// If y is too high, treat it as an open ceiling and return false.
+func (b Board) Get(x, y int) bool {
+	if x < 0 || x >= b.Width || y < b.Floor {
+		return true
+	}
+	if y >= b.Height() {
+		return false
+	}
+	return b.Rows[y-b.Floor]&(1<<x) != 0
+}
+
+func (b Board) Set(x, y int, val bool) {
+	// Panic if out of bounds
+	if x < 0 || x >= b.Width || y < b.Floor || y >= b.Height() {
+		panic(fmt.Sprintf("Out of bounds: %d, %d", x, y))
+	}
+	if val {
+		// fmt.Println("Setting ", x, y, " to true")
+		b.Rows[y-b.Floor] |= (1 << x)
+		// fmt.Println("1 << x: ", 1<<x)
+	} else {
+		b.Rows[y-b.Floor] &= ^(1 << x)
+	}
+}
+
+// Height of the tower, including any rows that have been pruned.
+func (b Board) Height() int {
+	return b.Floor + len(b.Rows)
+}
+
+func (b Board) PieceIsColiding(p Piece) bool {
+	for y := 0; y < p.Height; y++ {
+		for x := 0; x < p.Width; x++ {
+			if p.Get(x, y) && b.Get(x+p.X, y+p.Y) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (b Board) PlacePiece(p Piece) Board {
+	// Place the piece on the board, growing the size of the slice if needed.
+	// fmt.Println("Placing piece at ", p.X, p.Y, " board size is ", len(b.Rows))
+	for y := 0; y < p.Height; y++ {
+		// Grow the board if needed.
+		for y+p.Y >= b.Height() {
+			b.Rows = append(b.Rows, 0)
+		}
+		for x := 0; x < p.Width; x++ {
+			if p.Get(x, y) {
+				// fmt.Println("Setting ", x+p.X, y+p.Y, " to true")
+				b.Set(x+p.X, y+p.Y, true)
+			}
+		}
+	}
+	// fmt.Println("Placed piece at ", p.X, p.Y, " board size is ", len(b.Rows))
+	return b
+}
+
+// Prints the board as a string, with an ascii boarder for the walls and the bottom, but not the ceiling.
+func (b Board) String() string {
+	str := ""
+	// Print the board from top to bottom
+	for y := b.Height() - 1; y >= b.Floor; y-- {
+		str += "|"
+		for x := 0; x < b.Width; x++ {
+			if b.Get(x, y) {
+				str += "#"
+			} else {
+				str += "."
+			}
+		}
+		str += "|\n"
+	}
+	str += b.floorString()
+	return str
+}
+
+func (b Board) ToStringWithPiece(p Piece) string {
+	// Print the board and piece, from top to bottom.
+	// We start at 5 above the top of the board, to fit the piece.
+	top := intMax(b.Height()+6, p.Y+p.Height-1)
+	return b.rowsWithPiece(p, top, b.Floor) + b.floorString()
+}
+
+// Like ToStringWithPiece, but only shows the given number of rows, following
+// the piece as the tower grows. Rows that have been pruned are shown as solid
+// rock.
+func (b Board) Viewport(p Piece, rows int) string {
+	top := intMax(b.Height()+6, p.Y+p.Height-1)
+	bottom := top - rows + 1
+	if bottom >= 0 {
+		return strings.TrimSuffix(b.rowsWithPiece(p, top, bottom), "\n")
+	}
+	// Close enough to the bottom to show the floor, which takes up one of the
+	// rows.
+	return b.rowsWithPiece(p, rows-2, 0) + b.floorString()
+}
+
+// Prints the rows of the board from top to bottom, inclusive.
+func (b Board) rowsWithPiece(p Piece, top, bottom int) string {
+	var str strings.Builder
+	for y := top; y >= bottom; y-- {
+		str.WriteString("|")
+		for x := 0; x < b.Width; x++ {
+			if b.Get(x, y) {
+				str.WriteString("#")
+			} else if p.Get(x-p.X, y-p.Y) {
+				str.WriteString("O")
+			} else {
+				str.WriteString(".")
+			}
+		}
+		str.WriteString("|\n")
+	}
+	return str.String()
+}
+
+// Drops the rows at the bottom of the board that no piece can reach, because
+// they're covered over by the rows above.
+func (b Board) Prune() Board {
+	reachable := b.reachableRows(len(b.Rows))
+	// Anything below the lowest reachable row acts the same as the floor.
+	drop := len(b.Rows) - len(reachable)
+	if drop == 0 {
+		return b
+	}
+	n := copy(b.Rows, b.Rows[drop:])
+	b.Rows = b.Rows[:n]
+	b.Floor += drop
+	return b
+}
+
+// Finds which of the empty cells in the board could be filled by a falling
+// piece, by flood filling down and sideways from above the top of the tower.
+// Returns the reachable cells in each row as a bitmask, starting from the top
+// row of the board and going down until the lowest row with a reachable cell,
+// or until maxRows rows.
+func (b Board) reachableRows(maxRows int) []uint64 {
+	if len(b.Rows) == 0 {
+		return nil
+	}
+	// Everything above the tower is empty, so the whole row is reachable.
+	full := b.fullRow()
+	above := full
+	rows := make([]uint64, 0)
+	for y := len(b.Rows) - 1; y >= 0 && len(rows) < maxRows; y-- {
+		// Cells you can fall into from the row above.
+		row := above &^ b.Rows[y]
+		if row == 0 {
+			break
+		}
+		// Then spread sideways until we hit something.
+		for {
+			spread := (row | row<<1 | row>>1) &^ b.Rows[y] & full
+			if spread == row {
+				break
+			}
+			row = spread
+		}
+		rows = append(rows, row)
+		above = row
+	}
+	return rows
+}
+
+// Removes any completed rows between fromY (inclusive) and toY (exclusive),
+// moving the rows above them down. Returns the new board and how many rows
+// were cleared.
+func (b Board) ClearRows(fromY, toY int) (Board, int) {
+	full := b.fullRow()
+	from := intMax(fromY-b.Floor, 0)
+	to := toY - b.Floor
+	if to > len(b.Rows) {
+		to = len(b.Rows)
+	}
+	kept := from
+	for y := from; y < len(b.Rows); y++ {
+		if y < to && b.Rows[y] == full {
+			continue
+		}
+		b.Rows[kept] = b.Rows[y]
+		kept++
+	}
+	cleared := len(b.Rows) - kept
+	b.Rows = b.Rows[:kept]
+	return b, cleared
+}
+
+func (b Board) floorString() string {
+	return "+" + strings.Repeat("-", b.Width) + "+"
+}
+
+func intMax(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
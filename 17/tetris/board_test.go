@@ -0,0 +1,86 @@
+package tetris
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestPrune(t *testing.T) {
+	b := NewBoard(7)
+	b.Rows = []uint64{
+		0b0011110,
+		0b1111111,
+		0b0001000,
+		0b0111111,
+		0b0000001,
+	}
+	// Pieces can fall down the gap on the right into the middle row, but the
+	// full row stops them getting any further.
+	b = b.Prune()
+	if b.Floor != 2 || b.Height() != 5 || len(b.Rows) != 3 {
+		t.Fatalf("Expected 2 rows to be pruned, got floor %d, rows %v", b.Floor, b.Rows)
+	}
+	if !b.Get(0, 1) || !b.Get(6, 0) {
+		t.Errorf("Expected pruned rows to act like the floor")
+	}
+	if b.Get(6, 3) || !b.Get(0, 3) || b.Get(1, 4) || !b.Get(3, 2) || b.Get(4, 2) {
+		t.Errorf("Expected the top rows to be unchanged, got\n%s", b)
+	}
+
+	// Nothing to prune if there's a gap all the way down.
+	b = NewBoard(7)
+	b.Rows = []uint64{0b0111111, 0b0111111}
+	if b = b.Prune(); b.Floor != 0 {
+		t.Errorf("Expected nothing to be pruned, got floor %d", b.Floor)
+	}
+}
+
+// Pruning shouldn't change where any of the pieces land.
+func TestPruningMatchesUnpruned(t *testing.T) {
+	pruned := NewSimulator(exampleJets)
+	unpruned := NewSimulator(exampleJets)
+	unpruned.pruneAt = math.MaxInt
+
+	for i := 0; i < 20000; i++ {
+		pruned.DropPiece()
+		unpruned.DropPiece()
+		if pruned.Height() != unpruned.Height() || pruned.Piece.X != unpruned.Piece.X {
+			t.Fatalf("Pruned and unpruned boards differ after %d pieces", i+1)
+		}
+	}
+	if len(pruned.Board.Rows) > 2*minPruneRows {
+		t.Errorf("Expected the pruned board to stay small, got %d rows", len(pruned.Board.Rows))
+	}
+}
+
+func BenchmarkDropPiece(b *testing.B) {
+	sim := NewSimulator(exampleJets)
+	for i := 0; i < b.N; i++ {
+		sim.DropPiece()
+	}
+}
+
+func TestViewport(t *testing.T) {
+	sim := NewSimulator(exampleJets)
+	for i := 0; i < 100; i++ {
+		sim.DropPiece()
+	}
+	view := sim.Board.Viewport(sim.Piece, 12)
+	lines := strings.Split(view, "\n")
+	if len(lines) != 12 {
+		t.Fatalf("Expected 12 rows, got %d:\n%s", len(lines), view)
+	}
+	// The top of the whole board should match the viewport.
+	full := sim.Board.ToStringWithPiece(sim.Piece)
+	if !strings.HasPrefix(full, view) {
+		t.Errorf("Expected the viewport to be the top of the board, got\n%s", view)
+	}
+
+	// Near the bottom, the floor is shown.
+	sim = NewSimulator(exampleJets)
+	view = sim.Board.Viewport(sim.Piece, 12)
+	if lines := strings.Split(view, "\n"); len(lines) != 12 || lines[11] != "+-------+" || lines[7] != "|..OOOO.|" {
+		t.Errorf("Unexpected viewport of an empty board:\n%s", view)
+	}
+}
@@ -0,0 +1,143 @@
+package tetris
+
+import (
+	"fmt"
+	"strings"
+)
+
+// What happens when pieces come to rest.
+type Rules int
+
+const (
+	// Rows are never cleared, like in the puzzle.
+	PuzzleRules Rules = iota
+	// Completed rows are cleared and score points, and the rows above them
+	// fall down to fill the gap, like in Tetris. Blocks don't fall into any
+	// holes under them, the rows just move down as a whole.
+	LineClearRules
+)
+
+// Settings for the chamber and the pieces that fall into it.
+type Config struct {
+	// How many columns wide the chamber is, up to MaxWidth.
+	Width int
+	// The pieces to drop, in order. They repeat once they run out.
+	Shapes [][]uint64
+	// Where new pieces appear: SpawnX columns from the left wall, and SpawnY
+	// rows above the top of the tower.
+	SpawnX int
+	SpawnY int
+	Rules  Rules
+}
+
+// The chamber and pieces from the puzzle.
+var DefaultConfig = Config{
+	Width:  7,
+	Shapes: PieceShapes,
+	SpawnX: 2,
+	SpawnY: 3,
+}
+
+func (c Config) Validate() error {
+	if c.Width < 1 || c.Width > MaxWidth {
+		return fmt.Errorf("chamber width must be between 1 and %d, got %d", MaxWidth, c.Width)
+	}
+	if len(c.Shapes) == 0 {
+		return fmt.Errorf("no piece shapes")
+	}
+	if c.Rules != PuzzleRules && c.Rules != LineClearRules {
+		return fmt.Errorf("unknown rules %d", c.Rules)
+	}
+	if c.SpawnX < 0 || c.SpawnY < 0 {
+		return fmt.Errorf("spawn offset can't be negative, got %d, %d", c.SpawnX, c.SpawnY)
+	}
+	for i, shape := range c.Shapes {
+		p := NewPiece(shape)
+		if p.Width == 0 {
+			return fmt.Errorf("piece %d is empty", i)
+		}
+		if c.SpawnX+p.Width > c.Width {
+			return fmt.Errorf("piece %d is %d wide, which doesn't fit in the chamber %d from the left wall", i, p.Width, c.SpawnX)
+		}
+	}
+	return nil
+}
+
+// Parses piece shapes drawn the way they look, with '#' for the parts of the
+// piece and '.' for gaps. Pieces are separated by blank lines, e.g.
+//
+//	.#.
+//	###
+//	.#.
+//
+//	..#
+//	..#
+//	###
+func ParseShapes(s string) ([][]uint64, error) {
+	shapes := make([][]uint64, 0)
+	rows := make([]uint64, 0)
+	// Finishes off the piece we've been reading, if there is one.
+	endShape := func(lineNum int) error {
+		if len(rows) == 0 {
+			return nil
+		}
+		// The rows were read top to bottom, but shapes are stored bottom first.
+		shape := make([]uint64, len(rows))
+		for i, row := range rows {
+			shape[len(rows)-1-i] = row
+		}
+		// Drop any empty columns on the left, so the piece spawns in the right
+		// place.
+		for all := orRows(shape); all != 0 && all&1 == 0; all >>= 1 {
+			for i := range shape {
+				shape[i] >>= 1
+			}
+		}
+		if shape[0] == 0 || shape[len(shape)-1] == 0 {
+			return fmt.Errorf("line %d: piece has an empty row at the top or bottom", lineNum)
+		}
+		shapes = append(shapes, shape)
+		rows = rows[:0]
+		return nil
+	}
+
+	lines := strings.Split(strings.ReplaceAll(s, "\r\n", "\n"), "\n")
+	for i, line := range lines {
+		line = strings.TrimRight(line, " \t")
+		if line == "" {
+			if err := endShape(i); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if len(line) > MaxWidth {
+			return nil, fmt.Errorf("line %d: piece is wider than %d", i+1, MaxWidth)
+		}
+		var row uint64
+		for x, c := range line {
+			switch c {
+			case '#':
+				row |= 1 << x
+			case '.':
+			default:
+				return nil, fmt.Errorf("line %d, column %d: unexpected character %q", i+1, x+1, c)
+			}
+		}
+		rows = append(rows, row)
+	}
+	if err := endShape(len(lines)); err != nil {
+		return nil, err
+	}
+	if len(shapes) == 0 {
+		return nil, fmt.Errorf("no pieces found")
+	}
+	return shapes, nil
+}
+
+func orRows(shape []uint64) uint64 {
+	var all uint64
+	for _, row := range shape {
+		all |= row
+	}
+	return all
+}
@@ -0,0 +1,123 @@
+package tetris
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const defaultShapesText = `
+####
+
+.#.
+###
+.#.
+
+..#
+..#
+###
+
+#
+#
+#
+#
+
+##
+##
+`
+
+func TestParseShapes(t *testing.T) {
+	shapes, err := ParseShapes(defaultShapesText)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(shapes, PieceShapes) {
+		t.Errorf("Expected %v, got %v", PieceShapes, shapes)
+	}
+
+	// Empty columns on the left are dropped.
+	shapes, err = ParseShapes("..#\n.##")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(shapes, [][]uint64{{0b11, 0b10}}) {
+		t.Errorf("Expected the piece to be shifted left, got %v", shapes)
+	}
+}
+
+func TestParseShapesErrors(t *testing.T) {
+	tests := []struct {
+		input string
+		err   string
+	}{
+		{"", "no pieces"},
+		{"\n\n", "no pieces"},
+		{"#.#\n#x#", "line 2, column 2"},
+		{"...\n###", "empty row"},
+		{strings.Repeat("#", MaxWidth+1), "wider than"},
+	}
+	for _, test := range tests {
+		_, err := ParseShapes(test.input)
+		if err == nil || !strings.Contains(err.Error(), test.err) {
+			t.Errorf("ParseShapes(%q): expected error containing %q, got %v", test.input, test.err, err)
+		}
+	}
+}
+
+func TestConfigValidate(t *testing.T) {
+	tests := []Config{
+		{Width: 0, Shapes: PieceShapes},
+		{Width: MaxWidth + 1, Shapes: PieceShapes},
+		{Width: 7},
+		{Width: 7, Shapes: PieceShapes, SpawnX: 4},
+		{Width: 7, Shapes: [][]uint64{{0}}},
+	}
+	for _, config := range tests {
+		if _, err := NewSimulatorWithConfig(exampleJets, config); err == nil {
+			t.Errorf("Expected %+v to be invalid", config)
+		}
+	}
+}
+
+const pentominoes = `
+#####
+
+##
+#
+#
+#
+
+.#
+###
+..#
+`
+
+// A wide chamber uses the full uint64 rows, so the cycle detection should
+// still agree with just dropping every piece.
+func TestWideChamber(t *testing.T) {
+	shapes, err := ParseShapes(pentominoes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	config := Config{Width: MaxWidth, Shapes: shapes, SpawnX: 30, SpawnY: 5}
+
+	sim, err := NewSimulatorWithConfig(exampleJets, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cycle, err := sim.FindCycle(MaxCyclePieces)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sim, _ = NewSimulatorWithConfig(exampleJets, config)
+	for n := 0; n < 5000; n++ {
+		if got := cycle.HeightAfter(int64(n)); got != int64(sim.Height()) {
+			t.Fatalf("Height after %d pieces is %d, cycle says %d", n, sim.Height(), got)
+		}
+		sim.DropPiece()
+	}
+	if !strings.HasSuffix(NewBoard(MaxWidth).String(), "+"+strings.Repeat("-", MaxWidth)+"+") {
+		t.Errorf("Expected the floor to be as wide as the chamber")
+	}
+}
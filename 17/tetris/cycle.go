@@ -0,0 +1,154 @@
+package tetris
+
+import (
+	"encoding/binary"
+	"fmt"
+	"hash/fnv"
+)
+
+// A repeating pattern in how the tower grows. Once the cycle starts, every
+// Length pieces add exactly HeightGain to the height of the tower.
+type Cycle struct {
+	// How many pieces had been dropped when the cycle first started.
+	Start int
+	// How many pieces are dropped in each repeat of the cycle.
+	Length     int
+	HeightGain int
+
+	// The piece count when we started looking for the cycle, and the height
+	// of the tower after each piece from then until the end of the first
+	// repeat of the cycle. heights[i] is the height after First + i pieces.
+	First   int
+	heights []int
+}
+
+// Works out the height of the tower after n pieces, without having to
+// simulate them all. n has to be at least First.
+func (c Cycle) HeightAfter(n int64) int64 {
+	if n < int64(c.First) {
+		panic(fmt.Sprintf("Can't get the height after %d pieces, the cycle was found from piece %d", n, c.First))
+	}
+	start := int64(c.Start)
+	if n < start {
+		return int64(c.heights[n-int64(c.First)])
+	}
+	loops := (n - start) / int64(c.Length)
+	remainder := (n - start) % int64(c.Length)
+	return int64(c.heights[start+remainder-int64(c.First)]) + loops*int64(c.HeightGain)
+}
+
+// Drops pieces until the simulation gets back to a state it's already been
+// in, which means it'll repeat from there. Gives up and returns an error if no
+// cycle is found after maxPieces pieces.
+func (s *Simulator) FindCycle(maxPieces int) (Cycle, error) {
+	first := s.PieceIndex
+	heights := []int{s.Height()}
+	// The lowest row each piece touched on the way down, which is the row
+	// under where it came to rest. lowest[i] is for piece First + i.
+	lowest := make([]int, 0)
+	// Maps a hash of each state to the piece count when we saw it. Keeping
+	// every fingerprint would take too much memory, so a matching hash only
+	// means we might have found a cycle.
+	seen := map[[16]byte]int{hashFingerprint(s.fingerprint()): first}
+
+	// The fingerprint only covers the top of the tower, so two states with the
+	// same fingerprint can still play out differently if a piece falls further
+	// down than that. But if none of the pieces in between touched anything
+	// below the top of the first state's fingerprint, the second state will do
+	// exactly what the first one did, and so on forever. As we only have the
+	// hash of the first state, we keep the fingerprint of the second one and
+	// check that it comes back exactly one cycle later.
+	var candidate *Cycle
+	var candidateKey string
+	rejected := 0
+	for i := 0; i < maxPieces; i++ {
+		s.DropPiece()
+		heights = append(heights, s.Height())
+		lowest = append(lowest, s.LastPiece.Y-1)
+		key := s.fingerprint()
+
+		if candidate != nil && s.PieceIndex == candidate.Start+candidate.Length {
+			startHeight := heights[candidate.Start-first]
+			if key == candidateKey && minOf(lowest[candidate.Start-first:]) >= startHeight-fingerprintDepth {
+				candidate.HeightGain = s.Height() - startHeight
+				candidate.heights = heights
+				return *candidate, nil
+			}
+			// The hashes matched by chance.
+			candidate = nil
+		}
+
+		hash := hashFingerprint(key)
+		if prev, ok := seen[hash]; ok && candidate == nil {
+			if minOf(lowest[prev-first:]) >= heights[prev-first]-fingerprintDepth {
+				candidate = &Cycle{
+					Start:  s.PieceIndex,
+					Length: s.PieceIndex - prev,
+					First:  first,
+				}
+				candidateKey = key
+			} else {
+				rejected++
+			}
+		}
+		seen[hash] = s.PieceIndex
+	}
+	if rejected > 0 {
+		return Cycle{}, fmt.Errorf("no cycle found after %d pieces, %d possible cycles were rejected because pieces fell more than %d rows below the top of the tower", maxPieces, rejected, fingerprintDepth)
+	}
+	return Cycle{}, fmt.Errorf("no cycle found after %d pieces", maxPieces)
+}
+
+func minOf(values []int) int {
+	min := values[0]
+	for _, v := range values[1:] {
+		if v < min {
+			min = v
+		}
+	}
+	return min
+}
+
+// The most pieces HeightAfter will drop looking for a cycle.
+const MaxCyclePieces = 1000000
+
+// Works out the height of the tower after n pieces have been dropped using the
+// given jets.
+func HeightAfter(jets string, n int64) (int64, error) {
+	s, err := NewSimulatorWithConfig(jets, DefaultConfig)
+	if err != nil {
+		return 0, err
+	}
+	cycle, err := s.FindCycle(MaxCyclePieces)
+	if err != nil {
+		return 0, err
+	}
+	return cycle.HeightAfter(n), nil
+}
+
+// How many rows from the top of the tower are included in the fingerprint.
+const fingerprintDepth = 64
+
+// Describes the next jet, the next shape, and the top fingerprintDepth rows of
+// the tower, with any rows that have been pruned counted as solid.
+func (s *Simulator) fingerprint() string {
+	b := s.Board
+	key := make([]byte, 0, 8*fingerprintDepth+16)
+	key = append(key, fmt.Sprintf("%d,%d:", s.JetIndex, s.ShapeIndex())...)
+	for y := b.Height() - 1; y >= b.Height()-fingerprintDepth; y-- {
+		row := b.fullRow()
+		if y >= b.Floor {
+			row = b.Rows[y-b.Floor]
+		}
+		key = binary.LittleEndian.AppendUint64(key, row)
+	}
+	return string(key)
+}
+
+func hashFingerprint(key string) [16]byte {
+	h := fnv.New128a()
+	h.Write([]byte(key))
+	var sum [16]byte
+	h.Sum(sum[:0])
+	return sum
+}
@@ -0,0 +1,154 @@
+package tetris
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+func TestHeightAfterExample(t *testing.T) {
+	tests := []struct {
+		pieces int64
+		want   int64
+	}{
+		{0, 0},
+		{1, 1},
+		{2022, 3068},
+		{1000000000000, 1514285714288},
+	}
+	for _, test := range tests {
+		got, err := HeightAfter(exampleJets, test.pieces)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != test.want {
+			t.Errorf("HeightAfter(%d) = %d, want %d", test.pieces, got, test.want)
+		}
+	}
+}
+
+func TestHeightAfterInvalidJets(t *testing.T) {
+	if _, err := HeightAfter("<<x>", 10); err == nil {
+		t.Errorf("Expected an error for invalid jets")
+	}
+}
+
+// The cycle should predict the same heights as just simulating every piece,
+// including for jets that don't repeat every len(jets) moves.
+func TestCycleMatchesSimulation(t *testing.T) {
+	rng := rand.New(rand.NewSource(17))
+	for i := 0; i < 20; i++ {
+		var jets strings.Builder
+		for j := 0; j < 1+rng.Intn(60); j++ {
+			if rng.Intn(2) == 0 {
+				jets.WriteByte('<')
+			} else {
+				jets.WriteByte('>')
+			}
+		}
+
+		cycle, err := NewSimulator(jets.String()).FindCycle(MaxCyclePieces)
+		if err != nil {
+			t.Fatalf("%s: %v", jets.String(), err)
+		}
+		sim := NewSimulator(jets.String())
+		for n := 0; n < 3000; n++ {
+			if got := cycle.HeightAfter(int64(n)); got != int64(sim.Height()) {
+				t.Fatalf("%s: height after %d pieces is %d, cycle says %d", jets.String(), n, sim.Height(), got)
+			}
+			sim.DropPiece()
+		}
+	}
+}
+
+// The top of the tower repeats here a few times before the heights do, because
+// pieces fall further down than the fingerprint goes.
+func TestCycleWithDeepGaps(t *testing.T) {
+	config := DefaultConfig
+	config.Width = 20
+	jets := ">>><<<><>>>>>><>>>>>>>><<><>><><<>><<>>>>>>>><<>"
+
+	sim, _ := NewSimulatorWithConfig(jets, config)
+	cycle, err := sim.FindCycle(MaxCyclePieces)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sim, _ = NewSimulatorWithConfig(jets, config)
+	for n := 0; n < 3000; n++ {
+		if got := cycle.HeightAfter(int64(n)); got != int64(sim.Height()) {
+			t.Fatalf("Height after %d pieces is %d, cycle says %d", n, sim.Height(), got)
+		}
+		sim.DropPiece()
+	}
+}
+
+// Random pieces drawn with '#' and '.', up to 4 by 4.
+func randomShapes(rng *rand.Rand) [][]uint64 {
+	var text strings.Builder
+	for i := 0; i < 1+rng.Intn(5); i++ {
+		width, height := 1+rng.Intn(4), 1+rng.Intn(4)
+		for y := 0; y < height; y++ {
+			for x := 0; x < width; x++ {
+				if rng.Intn(3) == 0 {
+					text.WriteByte('.')
+				} else {
+					text.WriteByte('#')
+				}
+			}
+			text.WriteByte('\n')
+		}
+		text.WriteByte('\n')
+	}
+	shapes, err := ParseShapes(text.String())
+	if err != nil {
+		// Some pieces end up with an empty row at the top or bottom.
+		return PieceShapes
+	}
+	return shapes
+}
+
+// Same as TestCycleMatchesSimulation, but with other widths and pieces, which
+// leave a lot more deep gaps. Not every config has a cycle that can be found,
+// but any cycle that is found has to be right.
+func TestCycleMatchesSimulationWithConfigs(t *testing.T) {
+	rng := rand.New(rand.NewSource(17))
+	tested, found := 0, 0
+	for i := 0; i < 200; i++ {
+		config := Config{
+			Width:  4 + rng.Intn(MaxWidth-3),
+			Shapes: PieceShapes,
+			SpawnX: rng.Intn(3),
+			SpawnY: rng.Intn(5),
+		}
+		if rng.Intn(2) == 0 {
+			config.Shapes = randomShapes(rng)
+		}
+		var jets strings.Builder
+		for j := 0; j < 1+rng.Intn(60); j++ {
+			jets.WriteByte("<>"[rng.Intn(2)])
+		}
+
+		sim, err := NewSimulatorWithConfig(jets.String(), config)
+		if err != nil {
+			// The pieces don't fit.
+			continue
+		}
+		tested++
+		cycle, err := sim.FindCycle(5000)
+		if err != nil {
+			continue
+		}
+		found++
+
+		sim, _ = NewSimulatorWithConfig(jets.String(), config)
+		for n := 0; n < 3000; n++ {
+			if got := cycle.HeightAfter(int64(n)); got != int64(sim.Height()) {
+				t.Fatalf("%+v, %s: height after %d pieces is %d, cycle says %d", config, jets.String(), n, sim.Height(), got)
+			}
+			sim.DropPiece()
+		}
+	}
+	if found < tested*3/4 {
+		t.Errorf("Only found cycles for %d of %d configs", found, tested)
+	}
+}
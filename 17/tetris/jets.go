@@ -0,0 +1,51 @@
+package tetris
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"unicode/utf8"
+)
+
+// Parses a line of jets, like ">>><<><>". Whitespace around the jets is
+// ignored, which includes the newline at the end of the input file.
+func ParseJets(s string) (string, error) {
+	// Keep track of how much was trimmed from the start so that errors point
+	// at the right place in the original.
+	jets := strings.TrimLeft(s, " \t\r\n")
+	offset := len(s) - len(jets)
+	jets = strings.TrimRight(jets, " \t\r\n")
+
+	if jets == "" {
+		return "", fmt.Errorf("no jets in the input")
+	}
+	if i := invalidJet(jets); i >= 0 {
+		c, _ := utf8.DecodeRuneInString(jets[i:])
+		return "", fmt.Errorf("position %d: invalid jet %q, expected '<' or '>'", offset+i+1, c)
+	}
+	return jets, nil
+}
+
+// Reads the jets from an input file.
+func ReadJetsFile(filename string) (string, error) {
+	input, err := os.ReadFile(filename)
+	if err != nil {
+		return "", err
+	}
+	jets, err := ParseJets(string(input))
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", filename, err)
+	}
+	return jets, nil
+}
+
+// Returns the index of the first character that isn't a jet, or -1 if they're
+// all fine.
+func invalidJet(jets string) int {
+	for i := 0; i < len(jets); i++ {
+		if jets[i] != '<' && jets[i] != '>' {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,39 @@
+package tetris
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseJets(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+		err   string
+	}{
+		{"<>\n", "<>", ""},
+		{" \t>><<\r\n\n", ">><<", ""},
+		{">", ">", ""},
+		{"", "", "no jets"},
+		{"\n  \n", "", "no jets"},
+		{"<<x>>", "", "position 3: invalid jet 'x'"},
+		{"\n <>\n<>", "", "position 5: invalid jet '\\n'"},
+		{"<é", "", "position 2: invalid jet 'é'"},
+	}
+	for _, test := range tests {
+		got, err := ParseJets(test.input)
+		if test.err == "" {
+			if err != nil || got != test.want {
+				t.Errorf("ParseJets(%q) = %q, %v, want %q", test.input, got, err, test.want)
+			}
+		} else if err == nil || !strings.Contains(err.Error(), test.err) {
+			t.Errorf("ParseJets(%q): expected error containing %q, got %v", test.input, test.err, err)
+		}
+	}
+
+	for _, jets := range []string{"", "<>\n"} {
+		if _, err := NewSimulatorWithConfig(jets, DefaultConfig); err == nil {
+			t.Errorf("Expected a simulator with jets %q to be invalid", jets)
+		}
+	}
+}
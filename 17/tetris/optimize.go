@@ -0,0 +1,252 @@
+package tetris
+
+import (
+	"encoding/binary"
+	"fmt"
+	"sort"
+)
+
+// The moves that can be made each tick while a piece falls, instead of
+// following the jets.
+const (
+	MoveLeft  = '<'
+	MoveRight = '>'
+	MoveNone  = '.'
+)
+
+// What the optimizer is trying to do.
+type Objective int
+
+const (
+	// Keep the tower as short as possible.
+	MinimizeHeight Objective = iota
+	// Fill as many rows as possible. Rows aren't cleared, so this is about
+	// packing the pieces tightly.
+	MaximizeRows
+)
+
+type OptimizeOptions struct {
+	Config Config
+	// How many pieces to drop.
+	Pieces int
+	// How many times each piece can be pushed left or right on the way down.
+	MovesPerPiece int
+	// How many of the best boards to keep after each piece. Bigger beams find
+	// better answers but take longer.
+	BeamWidth int
+	Objective Objective
+}
+
+// The moves chosen for each piece, and where they lead.
+type Plan struct {
+	// The move made on each tick for each piece, as a string of MoveLeft,
+	// MoveRight and MoveNone.
+	Moves         []string
+	Board         Board
+	Height        int
+	CompletedRows int
+}
+
+// Linked list of the moves for each piece, so beam states can share them.
+type moveHistory struct {
+	moves string
+	prev  *moveHistory
+}
+
+type beamState struct {
+	board   Board
+	history *moveHistory
+	score   boardScore
+}
+
+// Searches for the moves that give the best tower for the objective, using a
+// beam search over where each piece could land. This isn't guaranteed to find
+// the best answer unless the beam is wide enough to hold every board.
+func Optimize(opts OptimizeOptions) (Plan, error) {
+	if err := opts.Config.Validate(); err != nil {
+		return Plan{}, err
+	}
+	if opts.Config.Rules != PuzzleRules {
+		return Plan{}, fmt.Errorf("the optimizer doesn't support clearing rows")
+	}
+	if opts.Pieces < 0 || opts.MovesPerPiece < 0 || opts.BeamWidth < 1 {
+		return Plan{}, fmt.Errorf("pieces and moves can't be negative, and the beam has to be at least 1 wide")
+	}
+
+	beam := []beamState{{board: NewBoard(opts.Config.Width)}}
+	for i := 0; i < opts.Pieces; i++ {
+		shape := opts.Config.Shapes[i%len(opts.Config.Shapes)]
+		next := make([]beamState, 0)
+		// Different moves often lead to the same board, and there's no point
+		// keeping more than one of them.
+		seen := make(map[string]bool)
+
+		for _, state := range beam {
+			piece := NewPiece(shape)
+			piece.X = opts.Config.SpawnX
+			piece.Y = state.board.Height() + opts.Config.SpawnY
+
+			for _, placement := range state.board.placements(piece, opts.MovesPerPiece) {
+				board := state.board.Clone().PlacePiece(placement.piece)
+				key := board.key()
+				if seen[key] {
+					continue
+				}
+				seen[key] = true
+				next = append(next, beamState{
+					board:   board,
+					history: &moveHistory{moves: placement.moves, prev: state.history},
+					score:   scoreBoard(board),
+				})
+			}
+		}
+
+		// Stable so that ties are broken the same way every time.
+		sort.SliceStable(next, func(a, b int) bool {
+			return next[a].score.less(next[b].score, opts.Objective)
+		})
+		if len(next) > opts.BeamWidth {
+			next = next[:opts.BeamWidth]
+		}
+		beam = next
+	}
+
+	best := beam[0]
+	moves := make([]string, opts.Pieces)
+	for h, i := best.history, opts.Pieces-1; h != nil; h, i = h.prev, i-1 {
+		moves[i] = h.moves
+	}
+	return Plan{
+		Moves:         moves,
+		Board:         best.board,
+		Height:        best.board.Height(),
+		CompletedRows: best.board.CompletedRows(),
+	}, nil
+}
+
+// Everything used to compare boards, worked out once per board.
+type boardScore struct {
+	height        int
+	completedRows int
+	holes         int
+	bumpiness     int
+}
+
+func scoreBoard(b Board) boardScore {
+	return boardScore{
+		height:        b.Height(),
+		completedRows: b.CompletedRows(),
+		holes:         b.Holes(),
+		bumpiness:     b.Bumpiness(),
+	}
+}
+
+// Whether board a is better than board b. The objective comes first, then
+// fewer holes and a flatter top, which leave more room for later pieces.
+func (a boardScore) less(b boardScore, objective Objective) bool {
+	if objective == MaximizeRows && a.completedRows != b.completedRows {
+		return a.completedRows > b.completedRows
+	}
+	if a.height != b.height {
+		return a.height < b.height
+	}
+	if a.holes != b.holes {
+		return a.holes < b.holes
+	}
+	return a.bumpiness < b.bumpiness
+}
+
+func (b Board) key() string {
+	key := make([]byte, 0, 8*len(b.Rows))
+	for _, row := range b.Rows {
+		key = binary.LittleEndian.AppendUint64(key, row)
+	}
+	return string(key)
+}
+
+// Somewhere a piece can come to rest, and the moves to get it there.
+type placement struct {
+	piece Piece
+	moves string
+}
+
+// Finds everywhere the piece could come to rest when it can be pushed at most
+// maxMoves times. Every tick the piece can be pushed one column left or right,
+// and then falls one row, the same as with the jets.
+func (b Board) placements(p Piece, maxMoves int) []placement {
+	// Everything falls at the same speed, so all the ways the piece could be
+	// falling are at the same height. We only need to track the x position,
+	// and the fewest moves needed to get there.
+	type path struct {
+		used  int
+		moves string
+	}
+	falling := map[int]path{p.X: {}}
+	resting := make(map[[2]int]path)
+
+	for y := p.Y; len(falling) > 0; y-- {
+		next := make(map[int]path)
+		// Go through the columns in order, so ties are broken the same way
+		// every time.
+		for x := 0; x < b.Width; x++ {
+			curr, ok := falling[x]
+			if !ok {
+				continue
+			}
+			// Not moving comes first, so we prefer using fewer moves.
+			for _, move := range []byte{MoveNone, MoveLeft, MoveRight} {
+				newX := x
+				used := curr.used
+				if move != MoveNone {
+					if used >= maxMoves {
+						continue
+					}
+					newX += moveDX(move)
+					used++
+				}
+				p.X, p.Y = newX, y
+				if move != MoveNone && b.PieceIsColiding(p) {
+					// Pushing into a wall is the same as not moving.
+					continue
+				}
+				newPath := path{used: used, moves: curr.moves + string(move)}
+
+				// Then it falls, or comes to rest if it can't.
+				p.Y--
+				if b.PieceIsColiding(p) {
+					if existing, ok := resting[[2]int{newX, y}]; !ok || used < existing.used {
+						resting[[2]int{newX, y}] = newPath
+					}
+				} else if existing, ok := next[newX]; !ok || used < existing.used {
+					next[newX] = newPath
+				}
+			}
+		}
+		falling = next
+	}
+
+	placements := make([]placement, 0, len(resting))
+	for pos, path := range resting {
+		piece := p
+		piece.X, piece.Y = pos[0], pos[1]
+		placements = append(placements, placement{piece: piece, moves: path.moves})
+	}
+	sort.Slice(placements, func(i, j int) bool {
+		a, b := placements[i].piece, placements[j].piece
+		if a.Y != b.Y {
+			return a.Y < b.Y
+		}
+		return a.X < b.X
+	})
+	return placements
+}
+
+func moveDX(move byte) int {
+	switch move {
+	case MoveLeft:
+		return -1
+	case MoveRight:
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,127 @@
+package tetris
+
+import (
+	"testing"
+)
+
+// Drops each piece following the planned moves, to check they really lead to
+// the planned board.
+func replayPlan(t *testing.T, config Config, plan Plan, maxMoves int) Board {
+	board := NewBoard(config.Width)
+	for i, moves := range plan.Moves {
+		p := NewPiece(config.Shapes[i%len(config.Shapes)])
+		p.X = config.SpawnX
+		p.Y = board.Height() + config.SpawnY
+		used := 0
+		for j, move := range []byte(moves) {
+			if move != MoveNone {
+				used++
+				p.X += moveDX(move)
+				if board.PieceIsColiding(p) {
+					t.Fatalf("Piece %d was pushed into something on move %d", i, j)
+				}
+			}
+			p.Y--
+			if board.PieceIsColiding(p) != (j == len(moves)-1) {
+				t.Fatalf("Piece %d should come to rest after exactly %d moves", i, len(moves))
+			}
+		}
+		if used > maxMoves {
+			t.Fatalf("Piece %d used %d moves, only %d allowed", i, used, maxMoves)
+		}
+		p.Y++
+		board = board.PlacePiece(p)
+	}
+	return board
+}
+
+func TestOptimizeHeight(t *testing.T) {
+	opts := OptimizeOptions{
+		Config:        DefaultConfig,
+		Pieces:        30,
+		MovesPerPiece: 3,
+		BeamWidth:     50,
+		Objective:     MinimizeHeight,
+	}
+	plan, err := Optimize(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	board := replayPlan(t, opts.Config, plan, opts.MovesPerPiece)
+	if board.String() != plan.Board.String() || plan.Height != board.Height() {
+		t.Errorf("Replaying the moves gave a different board")
+	}
+
+	// Should do better than the jets from the example, and can't do better
+	// than filling every row completely.
+	sim := NewSimulator(exampleJets)
+	cells := 0
+	for sim.PieceIndex < opts.Pieces {
+		for _, row := range sim.Config.Shapes[sim.ShapeIndex()] {
+			for ; row != 0; row &= row - 1 {
+				cells++
+			}
+		}
+		sim.DropPiece()
+	}
+	if plan.Height >= sim.Height() {
+		t.Errorf("Expected a shorter tower than %d from the jets, got %d", sim.Height(), plan.Height)
+	}
+	if minHeight := (cells + opts.Config.Width - 1) / opts.Config.Width; plan.Height < minHeight {
+		t.Errorf("Height %d is impossibly short, the pieces need at least %d rows", plan.Height, minHeight)
+	}
+}
+
+func TestOptimizeRows(t *testing.T) {
+	// Blocks fill a chamber 4 wide perfectly.
+	config := Config{Width: 4, Shapes: [][]uint64{{0b11, 0b11}}, SpawnX: 0, SpawnY: 3}
+	plan, err := Optimize(OptimizeOptions{
+		Config:        config,
+		Pieces:        6,
+		MovesPerPiece: 2,
+		BeamWidth:     10,
+		Objective:     MaximizeRows,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plan.CompletedRows != 6 || plan.Height != 6 || plan.Board.Holes() != 0 {
+		t.Errorf("Expected 6 full rows, got %d rows, height %d:\n%s", plan.CompletedRows, plan.Height, plan.Board)
+	}
+	replayPlan(t, config, plan, 2)
+
+	// Without any moves, they just stack up in the corner.
+	plan, err = Optimize(OptimizeOptions{Config: config, Pieces: 3, BeamWidth: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plan.Height != 6 || plan.CompletedRows != 0 || plan.Moves[0] != "...." {
+		t.Errorf("Expected the blocks to fall straight down, got moves %v:\n%s", plan.Moves, plan.Board)
+	}
+}
+
+func TestBoardStats(t *testing.T) {
+	b := NewBoard(5)
+	b.Rows = []uint64{
+		0b11111,
+		0b10101,
+		0b00100,
+	}
+	if heights := b.ColumnHeights(); heights[0] != 2 || heights[1] != 1 || heights[2] != 3 || heights[3] != 1 || heights[4] != 2 {
+		t.Errorf("Unexpected column heights %v", heights)
+	}
+	if b.Holes() != 0 {
+		t.Errorf("Expected no holes, got %d", b.Holes())
+	}
+	if b.Bumpiness() != 1+2+2+1 {
+		t.Errorf("Expected bumpiness 6, got %d", b.Bumpiness())
+	}
+	if b.CompletedRows() != 1 {
+		t.Errorf("Expected 1 completed row, got %d", b.CompletedRows())
+	}
+
+	b.Rows = append(b.Rows, 0b11011)
+	if b.Holes() != 6 {
+		t.Errorf("Expected 6 holes, got %d", b.Holes())
+	}
+}
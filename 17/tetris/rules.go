@@ -0,0 +1,103 @@
+package tetris
+
+// What happened when a piece came to rest.
+type PieceStats struct {
+	// Which piece this was, counting from 0.
+	Piece int
+	// The row the bottom of the piece came to rest in.
+	Y int
+	// How many rows the piece completed, with LineClearRules.
+	RowsCleared int
+	// Points scored for clearing the rows.
+	Points int
+	// How many empty cells the piece covered over, before any rows were
+	// cleared. This is negative if the piece slid in under an overhang and
+	// filled more holes than it made.
+	HolesCreated int
+	// How uneven the top of the tower is once the piece has landed.
+	Bumpiness int
+	// Height of the tower once the piece has landed.
+	Height int
+}
+
+// Points for clearing 1, 2, 3 or 4 rows at once, like in the original Tetris.
+var linePoints = []int{0, 40, 100, 300, 1200}
+
+// Custom pieces can be taller than 4, so they can clear more rows than there
+// are points for. Each extra row is worth as much as one row of a Tetris.
+func pointsForRows(rows int) int {
+	last := len(linePoints) - 1
+	if rows <= last {
+		return linePoints[rows]
+	}
+	return linePoints[last] + (rows-last)*linePoints[last]/last
+}
+
+// Adds the current piece to the board, and clears rows or prunes the board
+// depending on the rules.
+func (s *Simulator) placePiece() {
+	p := s.Piece
+	stats := PieceStats{
+		Piece:        s.PieceIndex,
+		Y:            p.Y,
+		HolesCreated: s.holesCreated(p),
+	}
+
+	s.Board = s.Board.PlacePiece(p)
+	for x := 0; x < p.Width; x++ {
+		for y := p.Height - 1; y >= 0; y-- {
+			if p.Get(x, y) {
+				s.columnHeights[p.X+x] = intMax(s.columnHeights[p.X+x], p.Y+y+1)
+				break
+			}
+		}
+	}
+
+	if s.Config.Rules == LineClearRules {
+		// Only the rows the piece is in could have been completed.
+		s.Board, stats.RowsCleared = s.Board.ClearRows(p.Y, p.Y+p.Height)
+		if stats.RowsCleared > 0 {
+			stats.Points = pointsForRows(stats.RowsCleared)
+			s.columnHeights = s.Board.ColumnHeights()
+		}
+	} else if len(s.Board.Rows) >= s.pruneAt {
+		// Clearing rows can uncover rows that were out of reach, so we can
+		// only prune when rows are never cleared.
+		s.Board = s.Board.Prune()
+		s.pruneAt = 2*len(s.Board.Rows) + minPruneRows
+	}
+
+	stats.Bumpiness = bumpiness(s.columnHeights)
+	stats.Height = s.Height()
+	s.Score += stats.Points
+	s.RowsCleared += stats.RowsCleared
+	s.LastPiece = stats
+}
+
+// Counts how many new holes there would be if the piece came to rest where it
+// is, by looking at each column it covers.
+func (s *Simulator) holesCreated(p Piece) int {
+	holes := 0
+	for x := 0; x < p.Width; x++ {
+		colHeight := s.columnHeights[p.X+x]
+		top := -1
+		for y := 0; y < p.Height; y++ {
+			if !p.Get(x, y) {
+				continue
+			}
+			top = p.Y + y
+			if top < colHeight {
+				// Filling in a hole under an overhang.
+				holes--
+			}
+		}
+		// Everything between the old top of the column and the top of the
+		// piece that the piece doesn't fill becomes a hole.
+		for y := colHeight; y <= top; y++ {
+			if !p.Get(x, y-p.Y) {
+				holes++
+			}
+		}
+	}
+	return holes
+}
@@ -0,0 +1,93 @@
+package tetris
+
+import (
+	"testing"
+)
+
+// The stats are worked out as each piece lands, so they should match looking
+// at the whole board.
+func TestPieceStats(t *testing.T) {
+	for _, rules := range []Rules{PuzzleRules, LineClearRules} {
+		// A narrow chamber, so that rows actually get completed.
+		config := Config{Width: 4, Shapes: PieceShapes, SpawnX: 0, SpawnY: 3, Rules: rules}
+		sim, err := NewSimulatorWithConfig(exampleJets, config)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// Prune as often as possible, to check it doesn't mess up the column
+		// heights.
+		sim.pruneAt = 0
+
+		checkedHoles := 0
+		for i := 0; i < 500; i++ {
+			before := sim.Board.Clone()
+			sim.DropPiece()
+			stats := sim.LastPiece
+
+			if stats.Piece != i || stats.Height != sim.Height() {
+				t.Fatalf("Piece %d: wrong piece index %d or height %d", i, stats.Piece, stats.Height)
+			}
+			if stats.Bumpiness != sim.Board.Bumpiness() {
+				t.Fatalf("Piece %d: expected bumpiness %d, got %d", i, sim.Board.Bumpiness(), stats.Bumpiness)
+			}
+			if stats.RowsCleared == 0 && rules == PuzzleRules {
+				// Only compare holes against boards that haven't been pruned.
+				if before.Floor == sim.Board.Floor {
+					checkedHoles++
+					if holes := before.Holes() + stats.HolesCreated; holes != sim.Board.Holes() {
+						t.Fatalf("Piece %d: expected %d holes, got %d", i, sim.Board.Holes(), holes)
+					}
+				}
+			}
+		}
+		if rules == PuzzleRules && checkedHoles < 100 {
+			t.Errorf("Expected to check the holes after most pieces, only checked %d", checkedHoles)
+		}
+		if rules == PuzzleRules && (sim.Score != 0 || sim.RowsCleared != 0) {
+			t.Errorf("Expected no rows to be cleared with the puzzle rules")
+		}
+		if rules == LineClearRules && (sim.RowsCleared == 0 || sim.Board.CompletedRows() != 0) {
+			t.Errorf("Expected some rows to be cleared and none left full, got %d cleared", sim.RowsCleared)
+		}
+	}
+}
+
+func TestLineClearing(t *testing.T) {
+	// Blocks exactly fill a chamber 2 wide, so every piece clears 2 rows.
+	config := Config{Width: 2, Shapes: [][]uint64{{0b11, 0b11}}, SpawnY: 3, Rules: LineClearRules}
+	sim, err := NewSimulatorWithConfig("<", config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 5; i++ {
+		sim.DropPiece()
+		if sim.LastPiece.RowsCleared != 2 || sim.LastPiece.Points != 100 || sim.Height() != 0 {
+			t.Fatalf("Expected each piece to clear 2 rows, got %+v", sim.LastPiece)
+		}
+	}
+	if sim.Score != 500 || sim.RowsCleared != 10 {
+		t.Errorf("Expected 10 rows and 500 points, got %d rows and %d points", sim.RowsCleared, sim.Score)
+	}
+}
+
+func TestClearRows(t *testing.T) {
+	b := NewBoard(3)
+	b.Rows = []uint64{0b111, 0b101, 0b111, 0b111, 0b010, 0b111}
+	// Only rows 1 to 3 are checked, so the top and bottom rows stay.
+	b, cleared := b.ClearRows(1, 4)
+	if cleared != 2 {
+		t.Errorf("Expected 2 rows to be cleared, got %d", cleared)
+	}
+	want := "|###|\n|.#.|\n|#.#|\n|###|\n+---+"
+	if b.String() != want {
+		t.Errorf("Expected\n%s\ngot\n%s", want, b)
+	}
+}
+
+func TestPointsForRows(t *testing.T) {
+	for rows, want := range []int{0, 40, 100, 300, 1200, 1500, 1800} {
+		if got := pointsForRows(rows); got != want {
+			t.Errorf("pointsForRows(%d) = %d, want %d", rows, got, want)
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package tetris
+
+import (
+	"fmt"
+)
+
+// Drops pieces into the chamber one jet at a time. The fields can be read at
+// any point to see the state of the simulation.
+type Simulator struct {
+	// A string with characters '>' and '<' representing the jets of gas. They
+	// repeat once they run out.
+	Jets   string
+	Config Config
+	Board  Board
+	// The piece that's currently falling.
+	Piece Piece
+
+	// Index into Jets of the next jet to push the piece.
+	JetIndex int
+	// How many pieces have come to rest. This is also the index of the
+	// current piece in the sequence of pieces.
+	PieceIndex int
+	// How many jets have pushed pieces in total.
+	Moves int
+
+	// Points scored from clearing rows, with LineClearRules.
+	Score int
+	// How many rows have been cleared in total, with LineClearRules.
+	RowsCleared int
+	// What happened when the last piece came to rest.
+	LastPiece PieceStats
+
+	// The height of each column, kept up to date as pieces are placed so we
+	// don't have to search the board for it.
+	columnHeights []int
+	// The board gets pruned once it has this many rows.
+	pruneAt int
+}
+
+// The fewest rows the board will have before it's pruned. Pruning has to
+// flood fill the whole board, so we don't want to do it too often.
+const minPruneRows = 256
+
+// Creates a simulator for the chamber and pieces from the puzzle. Panics if
+// the jets aren't valid, so use ParseJets on any input first.
+func NewSimulator(jets string) *Simulator {
+	s, err := NewSimulatorWithConfig(jets, DefaultConfig)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+func NewSimulatorWithConfig(jets string, config Config) (*Simulator, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	if jets == "" {
+		return nil, fmt.Errorf("no jets")
+	}
+	if i := invalidJet(jets); i >= 0 {
+		return nil, fmt.Errorf("invalid jet %q at position %d", jets[i], i+1)
+	}
+	s := &Simulator{
+		Jets:    jets,
+		Config:  config,
+		Board:   NewBoard(config.Width),
+		pruneAt: minPruneRows,
+
+		columnHeights: make([]int, config.Width),
+	}
+	s.spawnPiece()
+	return s, nil
+}
+
+// Index of the current piece's shape in Config.Shapes.
+func (s *Simulator) ShapeIndex() int {
+	return s.PieceIndex % len(s.Config.Shapes)
+}
+
+// Height of the tower of pieces that have come to rest.
+func (s *Simulator) Height() int {
+	return s.Board.Height()
+}
+
+// Creates the next piece at the top of the board.
+func (s *Simulator) spawnPiece() {
+	s.Piece = NewPiece(s.Config.Shapes[s.ShapeIndex()])
+	s.Piece.X = s.Config.SpawnX
+	s.Piece.Y = s.Board.Height() + s.Config.SpawnY
+}
+
+// Pushes the current piece with the next jet, then moves it down one. If it
+// can't move down, it comes to rest and the next piece is created. Returns
+// whether the piece came to rest.
+func (s *Simulator) Step() bool {
+	// Try move the piece. If it collides, move it back. The jets have already
+	// been checked, so anything that isn't '>' is '<'.
+	if s.Jets[s.JetIndex] == '>' {
+		s.Piece.X++
+		if s.Board.PieceIsColiding(s.Piece) {
+			s.Piece.X--
+		}
+	} else {
+		s.Piece.X--
+		if s.Board.PieceIsColiding(s.Piece) {
+			s.Piece.X++
+		}
+	}
+	s.JetIndex = (s.JetIndex + 1) % len(s.Jets)
+	s.Moves++
+
+	// Move the piece one down.
+	s.Piece.Y--
+	// If the piece collides, place it on the board and create a new piece.
+	if s.Board.PieceIsColiding(s.Piece) {
+		s.Piece.Y++
+		s.placePiece()
+		s.PieceIndex++
+		s.spawnPiece()
+		return true
+	}
+	return false
+}
+
+// Steps until the current piece comes to rest.
+func (s *Simulator) DropPiece() {
+	for !s.Step() {
+	}
+}
@@ -0,0 +1,43 @@
+package tetris
+
+import (
+	"testing"
+)
+
+const exampleJets = ">>><<><>><<<>><>>><<<>>><<<><<<>><>><<>>"
+
+func TestSimulatorExample(t *testing.T) {
+	sim := NewSimulator(exampleJets)
+	for i := 0; i < 2022; i++ {
+		sim.DropPiece()
+	}
+	if sim.Height() != 3068 {
+		t.Errorf("Expected height 3068, got %d", sim.Height())
+	}
+	if sim.PieceIndex != 2022 {
+		t.Errorf("Expected 2022 pieces, got %d", sim.PieceIndex)
+	}
+	if sim.JetIndex != sim.Moves%len(exampleJets) {
+		t.Errorf("Expected jet index %d, got %d", sim.Moves%len(exampleJets), sim.JetIndex)
+	}
+}
+
+func TestSimulatorStep(t *testing.T) {
+	sim := NewSimulator(exampleJets)
+	// The first piece is the horizontal line, which is pushed right three
+	// times and left once as it falls the three rows to the floor.
+	for i := 0; i < 3; i++ {
+		if sim.Step() {
+			t.Fatalf("Piece came to rest too early, after %d steps", i+1)
+		}
+	}
+	if !sim.Step() {
+		t.Fatalf("Expected the piece to come to rest")
+	}
+	if sim.Height() != 1 || sim.Board.String() != "|..####.|\n+-------+" {
+		t.Errorf("Unexpected board:\n%s", sim.Board)
+	}
+	if sim.ShapeIndex() != 1 || sim.Piece.Y != 4 {
+		t.Errorf("Expected the plus shape 3 rows above the tower, got shape %d at %d", sim.ShapeIndex(), sim.Piece.Y)
+	}
+}
@@ -0,0 +1,74 @@
+package tetris
+
+import (
+	"math/bits"
+)
+
+func (b Board) Clone() Board {
+	rows := make([]uint64, len(b.Rows), cap(b.Rows))
+	copy(rows, b.Rows)
+	b.Rows = rows
+	return b
+}
+
+// The height of each column, i.e. one more than the y of the highest rock in
+// it.
+func (b Board) ColumnHeights() []int {
+	heights := make([]int, b.Width)
+	for x := range heights {
+		heights[x] = b.Floor
+	}
+	// Work down from the top until every column has been found.
+	found := uint64(0)
+	for y := len(b.Rows) - 1; y >= 0 && found != b.fullRow(); y-- {
+		newCols := b.Rows[y] &^ found
+		for newCols != 0 {
+			x := bits.TrailingZeros64(newCols)
+			heights[x] = b.Floor + y + 1
+			newCols &= newCols - 1
+		}
+		found |= b.Rows[y]
+	}
+	return heights
+}
+
+// Counts empty cells that have rock somewhere above them in the same column.
+func (b Board) Holes() int {
+	holes := 0
+	covered := uint64(0)
+	for y := len(b.Rows) - 1; y >= 0; y-- {
+		holes += bits.OnesCount64(covered &^ b.Rows[y])
+		covered |= b.Rows[y]
+	}
+	return holes
+}
+
+// The total difference in height between neighbouring columns, which shows
+// how uneven the top of the tower is.
+func (b Board) Bumpiness() int {
+	return bumpiness(b.ColumnHeights())
+}
+
+func bumpiness(heights []int) int {
+	bumpiness := 0
+	for x := 1; x < len(heights); x++ {
+		diff := heights[x] - heights[x-1]
+		if diff < 0 {
+			diff = -diff
+		}
+		bumpiness += diff
+	}
+	return bumpiness
+}
+
+// Counts the rows that are completely filled.
+func (b Board) CompletedRows() int {
+	full := b.fullRow()
+	count := 0
+	for _, row := range b.Rows {
+		if row == full {
+			count++
+		}
+	}
+	return count
+}
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"advent2022/17/tetris"
+	"advent2022/17/viewer"
+)
+
+// Watches the rocks fall, e.g.
+//
+//	go run ./17/view -delay 50ms 17/input.txt
+//	go run ./17/view -pieces 50 -record rocks.gif -quiet 17/input.txt
+func main() {
+	rows := flag.Int("rows", 30, "how many rows of the chamber to show")
+	delay := flag.Duration("delay", 100*time.Millisecond, "how long to wait between moves")
+	paused := flag.Bool("paused", false, "start paused")
+	pieces := flag.Int("pieces", 0, "stop after this many pieces, or 0 to keep going")
+	record := flag.String("record", "", "record the frames to a .cast (asciicast) or .gif file")
+	quiet := flag.Bool("quiet", false, "don't show anything, just record as fast as possible")
+	clearRows := flag.Bool("clear-rows", false, "clear completed rows and keep score, like in Tetris")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [flags] <input file>\n", os.Args[0])
+		flag.PrintDefaults()
+		fmt.Fprintln(flag.CommandLine.Output(), "\nWhile running, type a command and press enter:\n  "+viewer.Help)
+	}
+	flag.Parse()
+
+	if flag.NArg() != 1 || *rows < 2 {
+		flag.Usage()
+		os.Exit(1)
+	}
+	if *quiet && (*record == "" || *pieces == 0) {
+		fmt.Fprintln(os.Stderr, "-quiet needs -record and -pieces")
+		os.Exit(1)
+	}
+
+	config := tetris.DefaultConfig
+	if *clearRows {
+		config.Rules = tetris.LineClearRules
+	}
+
+	if err := view(flag.Arg(0), config, *rows, *delay, *paused, *pieces, *record, *quiet); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func view(filename string, config tetris.Config, rows int, delay time.Duration, paused bool, pieces int, record string, quiet bool) (err error) {
+	jets, err := tetris.ReadJetsFile(filename)
+	if err != nil {
+		return err
+	}
+	sim, err := tetris.NewSimulatorWithConfig(jets, config)
+	if err != nil {
+		return err
+	}
+
+	v := &viewer.Viewer{
+		Sim:       sim,
+		Rows:      rows,
+		Delay:     delay,
+		Paused:    paused,
+		MaxPieces: pieces,
+	}
+
+	if record != "" {
+		recorder, finish, createErr := createRecorder(record, v.Sim.Config.Width+2, rows)
+		if createErr != nil {
+			return createErr
+		}
+		v.Recorder = recorder
+		defer func() {
+			// Report the first error, but make sure the recording is still
+			// written out.
+			if finishErr := finish(); err == nil {
+				err = finishErr
+			}
+		}()
+	}
+
+	if quiet {
+		return v.Run(nil)
+	}
+	v.Out = os.Stdout
+	commands := make(chan viewer.Command)
+	go readCommands(commands)
+	return v.Run(commands)
+}
+
+// Creates a recorder based on the file extension. The returned function
+// finishes writing the recording and closes the file.
+func createRecorder(filename string, width, height int) (viewer.Recorder, func() error, error) {
+	ext := filepath.Ext(filename)
+	if ext != ".cast" && ext != ".gif" {
+		return nil, nil, fmt.Errorf("don't know how to record to %s, use .cast or .gif", filename)
+	}
+	f, err := os.Create(filename)
+	if err != nil {
+		return nil, nil, err
+	}
+	w := bufio.NewWriter(f)
+
+	var recorder viewer.Recorder
+	if ext == ".cast" {
+		recorder, err = viewer.NewAsciicastRecorder(w, width, height)
+		if err != nil {
+			f.Close()
+			return nil, nil, err
+		}
+	} else {
+		recorder = viewer.NewGIFRecorder(w)
+	}
+
+	finish := func() error {
+		err := recorder.Close()
+		if err == nil {
+			err = w.Flush()
+		}
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+		return err
+	}
+	return recorder, finish, nil
+}
+
+// Sends each command the user types to the viewer.
+func readCommands(commands chan<- viewer.Command) {
+	scanner := bufio.NewScanner(os.Stdin)
+	for scanner.Scan() {
+		if command, ok := viewer.ParseCommand(scanner.Text()); ok {
+			commands <- command
+		}
+	}
+	close(commands)
+}
@@ -0,0 +1,143 @@
+package viewer
+
+import (
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/color"
+	"image/gif"
+	"io"
+	"strings"
+	"time"
+)
+
+// Saves frames of the simulation somewhere. Each frame is a board as drawn
+// by Board.Viewport, at the given time since the start of the recording.
+type Recorder interface {
+	Frame(text string, at time.Duration) error
+	// Finishes the recording. Nothing is guaranteed to be written until this
+	// is called.
+	Close() error
+}
+
+// Records frames as an asciicast (v2) file, which can be played back in a
+// terminal with asciinema.
+type AsciicastRecorder struct {
+	w io.Writer
+}
+
+// width and height are the size of the terminal in characters.
+func NewAsciicastRecorder(w io.Writer, width, height int) (*AsciicastRecorder, error) {
+	header, err := json.Marshal(map[string]interface{}{
+		"version": 2,
+		"width":   width,
+		"height":  height,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if _, err := fmt.Fprintf(w, "%s\n", header); err != nil {
+		return nil, err
+	}
+	return &AsciicastRecorder{w: w}, nil
+}
+
+func (r *AsciicastRecorder) Frame(text string, at time.Duration) error {
+	// Each frame clears the screen and redraws everything. Terminals need a
+	// carriage return as well as the newline.
+	data := "\033[H\033[2J" + strings.ReplaceAll(text, "\n", "\r\n")
+	event, err := json.Marshal([]interface{}{at.Seconds(), "o", data})
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(r.w, "%s\n", event)
+	return err
+}
+
+func (r *AsciicastRecorder) Close() error {
+	return nil
+}
+
+// How many pixels wide and tall each cell of the board is in a GIF.
+const gifCellSize = 4
+
+var gifPalette = color.Palette{
+	// Empty space
+	color.RGBA{0x10, 0x10, 0x18, 0xff},
+	// Rock
+	color.RGBA{0xb0, 0xa0, 0x90, 0xff},
+	// Falling piece
+	color.RGBA{0xf0, 0x80, 0x30, 0xff},
+	// Walls and floor
+	color.RGBA{0x60, 0x60, 0x70, 0xff},
+}
+
+// Records frames as an animated GIF. The frames are kept in memory until the
+// recorder is closed, so this is best kept to short recordings.
+type GIFRecorder struct {
+	w    io.Writer
+	anim gif.GIF
+	// When the last frame was shown, so we know how long it lasts for.
+	lastAt time.Duration
+}
+
+func NewGIFRecorder(w io.Writer) *GIFRecorder {
+	return &GIFRecorder{w: w}
+}
+
+func (r *GIFRecorder) Frame(text string, at time.Duration) error {
+	lines := strings.Split(strings.TrimRight(text, "\n"), "\n")
+	width := 0
+	for _, line := range lines {
+		width = intMax(width, len(line))
+	}
+	img := image.NewPaletted(image.Rect(0, 0, width*gifCellSize, len(lines)*gifCellSize), gifPalette)
+	for y, line := range lines {
+		for x, c := range line {
+			var index uint8
+			switch c {
+			case '#':
+				index = 1
+			case 'O':
+				index = 2
+			case '|', '+', '-':
+				index = 3
+			}
+			for py := 0; py < gifCellSize; py++ {
+				for px := 0; px < gifCellSize; px++ {
+					img.SetColorIndex(x*gifCellSize+px, y*gifCellSize+py, index)
+				}
+			}
+		}
+	}
+
+	if n := len(r.anim.Delay); n > 0 {
+		r.anim.Delay[n-1] = gifDelay(at - r.lastAt)
+	}
+	r.anim.Image = append(r.anim.Image, img)
+	r.anim.Delay = append(r.anim.Delay, gifDelay(0))
+	r.lastAt = at
+	return nil
+}
+
+func (r *GIFRecorder) Close() error {
+	if len(r.anim.Image) == 0 {
+		return fmt.Errorf("no frames to write to the gif")
+	}
+	// Hold the last frame for a bit before looping.
+	r.anim.Delay[len(r.anim.Delay)-1] = 200
+	return gif.EncodeAll(r.w, &r.anim)
+}
+
+// GIF delays are in hundredths of a second, and most viewers don't go below
+// 2.
+func gifDelay(d time.Duration) int {
+	return intMax(2, int(d/(10*time.Millisecond)))
+}
+
+func intMax(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,165 @@
+package viewer
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"advent2022/17/tetris"
+)
+
+// Things the user can do while the simulation is playing.
+type Command int
+
+const (
+	TogglePause Command = iota
+	// Does one move while paused.
+	StepOnce
+	Faster
+	Slower
+	Quit
+)
+
+const Help = "p: pause/resume, s or enter: step, +/-: speed up/slow down, q: quit (then press enter)"
+
+// Reads a command from a line the user typed. An empty line steps, so you can
+// hold down enter while paused.
+func ParseCommand(line string) (Command, bool) {
+	switch strings.TrimSpace(line) {
+	case "p":
+		return TogglePause, true
+	case "", "s":
+		return StepOnce, true
+	case "+", "f":
+		return Faster, true
+	case "-", "d":
+		return Slower, true
+	case "q":
+		return Quit, true
+	}
+	return 0, false
+}
+
+// The shortest and longest delays between moves.
+const minDelay = time.Millisecond
+const maxDelay = 5 * time.Second
+
+// Plays the simulation one move at a time, showing a window around the top
+// of the tower.
+type Viewer struct {
+	Sim *tetris.Simulator
+	// How many rows of the chamber to show.
+	Rows int
+	// How long to wait between moves.
+	Delay  time.Duration
+	Paused bool
+	// Stop once this many pieces have come to rest, or never if it's 0.
+	MaxPieces int
+
+	// Where to draw the frames, usually stdout. If it's nil nothing is shown
+	// and the simulation runs as fast as possible, which is useful for
+	// recording.
+	Out io.Writer
+	// If set, every frame is recorded to it. Frames are timed as if the
+	// simulation was never paused.
+	Recorder Recorder
+
+	// The time in the recording.
+	clock time.Duration
+}
+
+// Runs until MaxPieces is reached or the user quits. commands can be nil if
+// the user can't control the viewer.
+func (v *Viewer) Run(commands <-chan Command) error {
+	if err := v.frame(); err != nil {
+		return err
+	}
+	for v.MaxPieces == 0 || v.Sim.PieceIndex < v.MaxPieces {
+		var command Command
+		var ok bool
+		if v.Out == nil {
+			// Nothing to watch, so no point waiting.
+		} else if v.Paused {
+			if commands == nil {
+				return nil
+			}
+			command, ok = <-commands
+			if !ok {
+				return nil
+			}
+		} else {
+			select {
+			case command, ok = <-commands:
+				if !ok {
+					// Keep playing, but there's nothing more to listen to.
+					commands = nil
+				}
+			case <-time.After(v.Delay):
+			}
+		}
+
+		if ok {
+			switch command {
+			case TogglePause:
+				v.Paused = !v.Paused
+			case Faster:
+				v.Delay = clampDelay(v.Delay / 2)
+			case Slower:
+				v.Delay = clampDelay(v.Delay * 2)
+			case Quit:
+				return nil
+			}
+			if command != StepOnce {
+				// Redraw to show the new status.
+				if err := v.draw(); err != nil {
+					return err
+				}
+				continue
+			}
+		}
+
+		v.Sim.Step()
+		v.clock += v.Delay
+		if err := v.frame(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Draws the current state and records it.
+func (v *Viewer) frame() error {
+	if v.Recorder != nil {
+		if err := v.Recorder.Frame(v.Sim.Board.Viewport(v.Sim.Piece, v.Rows), v.clock); err != nil {
+			return err
+		}
+	}
+	return v.draw()
+}
+
+func (v *Viewer) draw() error {
+	if v.Out == nil {
+		return nil
+	}
+	status := fmt.Sprintf("pieces %d  height %d  delay %v", v.Sim.PieceIndex, v.Sim.Height(), v.Delay)
+	if v.Sim.Config.Rules == tetris.LineClearRules {
+		status += fmt.Sprintf("  rows %d  score %d", v.Sim.RowsCleared, v.Sim.Score)
+	}
+	if v.Paused {
+		status += "  (paused)"
+	}
+	// Clear the console and move the cursor to the top left
+	_, err := fmt.Fprintf(v.Out, "\033[H\033[2J%s\n%s\n%s\n", status, v.Sim.Board.Viewport(v.Sim.Piece, v.Rows), Help)
+	return err
+}
+
+func clampDelay(d time.Duration) time.Duration {
+	if d < minDelay {
+		return minDelay
+	}
+	if d > maxDelay {
+		return maxDelay
+	}
+	return d
+}
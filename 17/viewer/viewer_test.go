@@ -0,0 +1,125 @@
+package viewer
+
+import (
+	"bytes"
+	"encoding/json"
+	"image/gif"
+	"strings"
+	"testing"
+	"time"
+
+	"advent2022/17/tetris"
+)
+
+const exampleJets = ">>><<><>><<<>><>>><<<>>><<<><<<>><>><<>>"
+
+func TestRecordAsciicast(t *testing.T) {
+	var buf bytes.Buffer
+	recorder, err := NewAsciicastRecorder(&buf, 9, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	v := &Viewer{
+		Sim:       tetris.NewSimulator(exampleJets),
+		Rows:      10,
+		Delay:     50 * time.Millisecond,
+		MaxPieces: 3,
+		Recorder:  recorder,
+	}
+	if err := v.Run(nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := recorder.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	// The header, the starting frame and then a frame for every move.
+	if len(lines) != 2+v.Sim.Moves {
+		t.Fatalf("Expected %d lines, got %d", 2+v.Sim.Moves, len(lines))
+	}
+	var header map[string]int
+	if err := json.Unmarshal([]byte(lines[0]), &header); err != nil {
+		t.Fatal(err)
+	}
+	if header["version"] != 2 || header["width"] != 9 || header["height"] != 10 {
+		t.Errorf("Unexpected header %v", header)
+	}
+
+	var event []interface{}
+	if err := json.Unmarshal([]byte(lines[len(lines)-1]), &event); err != nil {
+		t.Fatal(err)
+	}
+	if at := event[0].(float64); at != float64(v.Sim.Moves)*0.05 {
+		t.Errorf("Expected the last frame at %vs, got %vs", float64(v.Sim.Moves)*0.05, at)
+	}
+	frame := event[2].(string)
+	// The tower is 6 high, so the bottom 3 rows are off the screen.
+	if strings.Count(frame, "\n") != 9 || !strings.HasSuffix(frame, "|..#....|\r\n|..#....|\r\n|####...|") {
+		t.Errorf("Unexpected last frame:\n%s", frame)
+	}
+}
+
+func TestRecordGIF(t *testing.T) {
+	var buf bytes.Buffer
+	recorder := NewGIFRecorder(&buf)
+	v := &Viewer{
+		Sim:       tetris.NewSimulator(exampleJets),
+		Rows:      10,
+		Delay:     100 * time.Millisecond,
+		MaxPieces: 2,
+		Recorder:  recorder,
+	}
+	if err := v.Run(nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := recorder.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	anim, err := gif.DecodeAll(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(anim.Image) != 1+v.Sim.Moves {
+		t.Errorf("Expected %d frames, got %d", 1+v.Sim.Moves, len(anim.Image))
+	}
+	bounds := anim.Image[0].Bounds()
+	if bounds.Dx() != 9*gifCellSize || bounds.Dy() != 10*gifCellSize {
+		t.Errorf("Unexpected frame size %v", bounds)
+	}
+	if anim.Delay[0] != 10 {
+		t.Errorf("Expected frames to last 10/100ths of a second, got %d", anim.Delay[0])
+	}
+}
+
+func TestControls(t *testing.T) {
+	var out bytes.Buffer
+	v := &Viewer{
+		Sim:    tetris.NewSimulator(exampleJets),
+		Rows:   10,
+		Delay:  time.Second,
+		Paused: true,
+		Out:    &out,
+	}
+	commands := make(chan Command, 10)
+	for _, line := range []string{"s", "", "+", "-", "-", "q"} {
+		command, ok := ParseCommand(line)
+		if !ok {
+			t.Fatalf("Couldn't parse %q", line)
+		}
+		commands <- command
+	}
+	if err := v.Run(commands); err != nil {
+		t.Fatal(err)
+	}
+	if v.Sim.Moves != 2 {
+		t.Errorf("Expected 2 steps while paused, got %d", v.Sim.Moves)
+	}
+	if v.Delay != 2*time.Second {
+		t.Errorf("Expected the delay to be 2s, got %v", v.Delay)
+	}
+	if !strings.Contains(out.String(), "(paused)") {
+		t.Errorf("Expected the output to show it's paused")
+	}
+}
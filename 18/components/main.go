@@ -0,0 +1,45 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"advent2022/18/voxel"
+)
+
+// Lists each piece of lava and each pocket of trapped air, e.g.
+//
+//	go run ./18/components 18/input.txt
+func main() {
+	if len(os.Args) != 2 {
+		fmt.Fprintf(os.Stderr, "Usage: %s <input file>\n", os.Args[0])
+		os.Exit(1)
+	}
+	points, err := voxel.ReadPoints(os.Args[1])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	labels := voxel.Label(voxel.GridFromPoints(points))
+	fmt.Printf("%d pieces of lava, %d pockets of air\n", len(labels.Lava), len(labels.Pockets))
+
+	fmt.Println("\nLava:")
+	for _, c := range labels.Lava {
+		printComponent(c)
+		if len(c.Touching) > 0 {
+			fmt.Printf("    contains air pockets %v\n", c.Touching)
+		}
+	}
+
+	fmt.Println("\nAir pockets:")
+	for _, c := range labels.Pockets {
+		printComponent(c)
+		fmt.Printf("    inside lava %v\n", c.Touching)
+	}
+}
+
+func printComponent(c voxel.Component) {
+	fmt.Printf("  %3d: %5d voxels, exterior area %5d, interior area %5d, bounds %v to %v, centroid (%.2f, %.2f, %.2f)\n",
+		c.ID, c.Count, c.ExteriorArea, c.InteriorArea, c.Min, c.Max, c.Centroid.X, c.Centroid.Y, c.Centroid.Z)
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"advent2022/18/voxel"
+)
+
+// Exports the outside of the lava as a mesh, e.g.
+//
+//	go run ./18/export -o droplet.stl 18/input.txt
+func main() {
+	output := flag.String("o", "", "file to write the mesh to, ending in .obj or .stl")
+	noGreedy := flag.Bool("no-greedy", false, "write a quad for every face instead of merging them")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s -o <output file> [flags] <input file>\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 1 || *output == "" {
+		flag.Usage()
+		os.Exit(1)
+	}
+
+	if err := export(flag.Arg(0), *output, !*noGreedy); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func export(filename, output string, greedy bool) error {
+	points, err := voxel.ReadPoints(filename)
+	if err != nil {
+		return err
+	}
+	mesh := voxel.ExteriorMesh(voxel.GridFromPoints(points), greedy)
+
+	var write func(*os.File) error
+	switch filepath.Ext(output) {
+	case ".obj":
+		write = func(f *os.File) error { return mesh.WriteOBJ(f) }
+	case ".stl":
+		write = func(f *os.File) error { return mesh.WriteSTL(f) }
+	default:
+		return fmt.Errorf("don't know how to write %s, use .obj or .stl", output)
+	}
+
+	f, err := os.Create(output)
+	if err != nil {
+		return err
+	}
+	if err := write(f); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	fmt.Printf("Wrote %d quads (%d triangles) covering an area of %d to %s\n", len(mesh.Quads), mesh.NumTriangles(), mesh.Area(), output)
+	return nil
+}
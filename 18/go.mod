@@ -0,0 +1,3 @@
+module advent2022/18
+
+go 1.19
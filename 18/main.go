@@ -0,0 +1,40 @@
+package main
+
+import (
+	"fmt"
+
+	"advent2022/18/voxel"
+)
+
+func readGrid(filename string) *voxel.Grid {
+	points, err := voxel.ReadPoints(filename)
+	if err != nil {
+		panic(err)
+	}
+	return voxel.GridFromPoints(points)
+}
+
+func solvePt1(filename string) {
+	grid := readGrid(filename)
+
+	// Count the faces with empty neighbours.
+	surfaceArea := voxel.SurfaceArea(grid)
+
+	// Print the result
+	fmt.Println(surfaceArea)
+}
+
+func solvePt2(filename string) {
+	grid := readGrid(filename)
+
+	// Only count faces that can be reached by filling in from the outside.
+	surfaceArea := voxel.ExteriorSurfaceArea(grid)
+
+	// Print the result
+	fmt.Println("Pt2", surfaceArea)
+}
+
+func main() {
+	solvePt1("18/input.txt")
+	solvePt2("18/input.txt")
+}
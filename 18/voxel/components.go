@@ -0,0 +1,124 @@
+package voxel
+
+import (
+	"sort"
+)
+
+// A group of voxels that are all connected through their faces. This is either
+// a piece of lava, or a pocket of air trapped inside the lava.
+type Component struct {
+	// Index of this component in Labels.Lava or Labels.Pockets.
+	ID     int
+	Voxels *Grid
+	Count  int
+
+	// For lava, the area of the faces that can be reached from outside.
+	// Always 0 for pockets of air.
+	ExteriorArea int
+	// For lava, the area of the faces touching trapped air. For pockets of
+	// air, the area of the walls around the pocket.
+	InteriorArea int
+
+	// The corners of the smallest box containing every voxel, inclusive.
+	Min Point
+	Max Point
+	// The average position of the voxels.
+	Centroid Vec3
+
+	// For lava, the pockets of air it touches. For pockets of air, the lava
+	// around them.
+	Touching []int
+}
+
+type Vec3 struct {
+	X float64
+	Y float64
+	Z float64
+}
+
+// The components of some lava, and the pockets of air trapped inside it.
+type Labels struct {
+	Lava    []Component
+	Pockets []Component
+
+	// Which component each voxel is in.
+	lavaIDs   map[Point]int
+	pocketIDs map[Point]int
+}
+
+// Finds which lava component or pocket of air a voxel is in. Returns nil for
+// both if the voxel is in the air outside.
+func (l *Labels) At(p Point) (lava *Component, pocket *Component) {
+	if id, ok := l.lavaIDs[p]; ok {
+		return &l.Lava[id], nil
+	}
+	if id, ok := l.pocketIDs[p]; ok {
+		return nil, &l.Pockets[id]
+	}
+	return nil, nil
+}
+
+// Splits the lava into pieces that are connected by their faces, and does the
+// same for the pockets of air trapped inside, and works out stats about each
+// of them.
+func Label(g *Grid) *Labels {
+	l := &Labels{
+		lavaIDs:   make(map[Point]int),
+		pocketIDs: make(map[Point]int),
+	}
+	l.Lava = newComponents(components(g, FaceDirections), l.lavaIDs)
+	l.Pockets = newComponents(components(EnclosedAir(g), FaceDirections), l.pocketIDs)
+
+	// Work out which faces of the lava touch what.
+	for i := range l.Lava {
+		lava := &l.Lava[i]
+		touching := make(map[int]bool)
+		lava.Voxels.ForEach(func(p Point) {
+			for _, direction := range FaceDirections {
+				neighbour := p.Add(direction)
+				if g.GetPoint(neighbour) {
+					continue
+				}
+				if id, ok := l.pocketIDs[neighbour]; ok {
+					lava.InteriorArea++
+					l.Pockets[id].InteriorArea++
+					touching[id] = true
+				} else {
+					lava.ExteriorArea++
+				}
+			}
+		})
+		for id := range touching {
+			lava.Touching = append(lava.Touching, id)
+			l.Pockets[id].Touching = append(l.Pockets[id].Touching, lava.ID)
+		}
+		sort.Ints(lava.Touching)
+	}
+	return l
+}
+
+// Creates a component for each group of voxels, and records which component
+// each voxel is in.
+func newComponents(groups []*Grid, ids map[Point]int) []Component {
+	labelled := make([]Component, len(groups))
+	for i, group := range groups {
+		min, max, _ := group.Bounds()
+		var sum Vec3
+		group.ForEach(func(p Point) {
+			ids[p] = i
+			sum.X += float64(p.X)
+			sum.Y += float64(p.Y)
+			sum.Z += float64(p.Z)
+		})
+		count := float64(group.Len())
+		labelled[i] = Component{
+			ID:       i,
+			Voxels:   group,
+			Count:    group.Len(),
+			Min:      min,
+			Max:      max,
+			Centroid: Vec3{sum.X / count, sum.Y / count, sum.Z / count},
+		}
+	}
+	return labelled
+}
@@ -0,0 +1,76 @@
+package voxel
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestLabelExample(t *testing.T) {
+	labels := Label(exampleGrid(t, Point{}))
+	if len(labels.Lava) != 6 || len(labels.Pockets) != 1 {
+		t.Fatalf("Expected 6 pieces of lava and 1 pocket, got %d and %d", len(labels.Lava), len(labels.Pockets))
+	}
+
+	exterior := 0
+	for _, c := range labels.Lava {
+		exterior += c.ExteriorArea
+		if c.InteriorArea != 1 || !reflect.DeepEqual(c.Touching, []int{0}) {
+			t.Errorf("Expected lava %d to touch the pocket with one face, got %d faces, touching %v", c.ID, c.InteriorArea, c.Touching)
+		}
+	}
+	if exterior != 58 {
+		t.Errorf("Expected a total exterior area of 58, got %d", exterior)
+	}
+
+	pocket := labels.Pockets[0]
+	if pocket.Count != 1 || pocket.InteriorArea != 6 || pocket.Min != (Point{2, 2, 5}) || len(pocket.Touching) != 6 {
+		t.Errorf("Unexpected pocket %+v", pocket)
+	}
+	if lava, p := labels.At(Point{2, 2, 5}); lava != nil || p == nil || p.ID != 0 {
+		t.Errorf("Expected {2, 2, 5} to be in the pocket")
+	}
+
+	big := labels.Lava[0]
+	if big.Count != 8 || big.Min != (Point{1, 1, 1}) || big.Max != (Point{3, 3, 4}) || big.Centroid != (Vec3{2, 2, 2.25}) {
+		t.Errorf("Unexpected stats for the biggest piece %+v", big)
+	}
+	if lava, p := labels.At(Point{2, 2, 4}); lava != &labels.Lava[0] || p != nil {
+		t.Errorf("Expected {2, 2, 4} to be in the biggest piece")
+	}
+	if lava, p := labels.At(Point{0, 0, 0}); lava != nil || p != nil {
+		t.Errorf("Expected {0, 0, 0} to be outside")
+	}
+}
+
+// A hollow cube, with a voxel floating in the middle of it.
+func TestLabelNested(t *testing.T) {
+	g := NewGrid()
+	for z := -2; z <= 2; z++ {
+		for y := -2; y <= 2; y++ {
+			for x := -2; x <= 2; x++ {
+				if x == -2 || x == 2 || y == -2 || y == 2 || z == -2 || z == 2 {
+					g.Set(x, y, z, true)
+				}
+			}
+		}
+	}
+	g.Set(0, 0, 0, true)
+
+	labels := Label(g)
+	if len(labels.Lava) != 2 || len(labels.Pockets) != 1 {
+		t.Fatalf("Expected 2 pieces of lava and 1 pocket, got %d and %d", len(labels.Lava), len(labels.Pockets))
+	}
+	shell, inner, pocket := labels.Lava[0], labels.Lava[1], labels.Pockets[0]
+	if shell.Count != 98 || shell.ExteriorArea != 150 || shell.InteriorArea != 54 || shell.Centroid != (Vec3{}) {
+		t.Errorf("Unexpected shell %+v", shell)
+	}
+	if inner.Count != 1 || inner.ExteriorArea != 0 || inner.InteriorArea != 6 {
+		t.Errorf("Unexpected inner voxel %+v", inner)
+	}
+	if pocket.Count != 26 || pocket.ExteriorArea != 0 || pocket.InteriorArea != 60 || !reflect.DeepEqual(pocket.Touching, []int{0, 1}) {
+		t.Errorf("Unexpected pocket %+v", pocket)
+	}
+	if pocket.Min != (Point{-1, -1, -1}) || pocket.Max != (Point{1, 1, 1}) {
+		t.Errorf("Unexpected pocket bounds %v to %v", pocket.Min, pocket.Max)
+	}
+}
@@ -0,0 +1,182 @@
+package voxel
+
+import (
+	"math/bits"
+	"sort"
+)
+
+type Point struct {
+	X int
+	Y int
+	Z int
+}
+
+func (p Point) Add(o Point) Point {
+	return Point{p.X + o.X, p.Y + o.Y, p.Z + o.Z}
+}
+
+func (p Point) Sub(o Point) Point {
+	return Point{p.X - o.X, p.Y - o.Y, p.Z - o.Z}
+}
+
+// The directions to each of the 6 faces of a cube.
+var FaceDirections = []Point{
+	{1, 0, 0},
+	{-1, 0, 0},
+	{0, 1, 0},
+	{0, -1, 0},
+	{0, 0, 1},
+	{0, 0, -1},
+}
+
+// Grids are split into cubes of chunkSize^3 voxels, and only chunks with
+// something in them are stored.
+const chunkBits = 4
+const chunkSize = 1 << chunkBits
+const chunkMask = chunkSize - 1
+
+// One bit per voxel.
+type chunk struct {
+	bits  [chunkSize * chunkSize * chunkSize / 64]uint64
+	count int
+}
+
+// A 3D grid of voxels that can be set or not. Coordinates can be anything,
+// including negative, and memory is only used for the parts of the grid that
+// have voxels set.
+type Grid struct {
+	chunks map[Point]*chunk
+	count  int
+}
+
+func NewGrid() *Grid {
+	return &Grid{
+		chunks: make(map[Point]*chunk),
+	}
+}
+
+// Which chunk a voxel is in, and its index within the chunk. Shifting rounds
+// down for negative numbers too, so -1 is in chunk -1 rather than chunk 0.
+func chunkIndex(x, y, z int) (Point, int) {
+	key := Point{x >> chunkBits, y >> chunkBits, z >> chunkBits}
+	index := (x & chunkMask) | (y&chunkMask)<<chunkBits | (z&chunkMask)<<(2*chunkBits)
+	return key, index
+}
+
+func (g *Grid) Get(x, y, z int) bool {
+	key, index := chunkIndex(x, y, z)
+	c, ok := g.chunks[key]
+	if !ok {
+		return false
+	}
+	return c.bits[index/64]&(1<<(index%64)) != 0
+}
+
+func (g *Grid) Set(x, y, z int, val bool) {
+	key, index := chunkIndex(x, y, z)
+	c, ok := g.chunks[key]
+	if !ok {
+		if !val {
+			return
+		}
+		c = &chunk{}
+		g.chunks[key] = c
+	}
+
+	bit := uint64(1) << (index % 64)
+	wasSet := c.bits[index/64]&bit != 0
+	if val == wasSet {
+		return
+	}
+	if val {
+		c.bits[index/64] |= bit
+		c.count++
+		g.count++
+	} else {
+		c.bits[index/64] &^= bit
+		c.count--
+		g.count--
+		// Free up chunks once they're empty.
+		if c.count == 0 {
+			delete(g.chunks, key)
+		}
+	}
+}
+
+func (g *Grid) GetPoint(p Point) bool {
+	return g.Get(p.X, p.Y, p.Z)
+}
+
+func (g *Grid) SetPoint(p Point, val bool) {
+	g.Set(p.X, p.Y, p.Z, val)
+}
+
+// How many voxels are set.
+func (g *Grid) Len() int {
+	return g.count
+}
+
+// Calls f with every voxel that's set, in no particular order.
+func (g *Grid) ForEach(f func(p Point)) {
+	for key, c := range g.chunks {
+		base := Point{key.X << chunkBits, key.Y << chunkBits, key.Z << chunkBits}
+		for i, word := range c.bits {
+			for word != 0 {
+				index := i*64 + bits.TrailingZeros64(word)
+				word &= word - 1
+				f(base.Add(Point{
+					index & chunkMask,
+					(index >> chunkBits) & chunkMask,
+					index >> (2 * chunkBits),
+				}))
+			}
+		}
+	}
+}
+
+// All the voxels that are set, sorted by z, then y, then x.
+func (g *Grid) Points() []Point {
+	points := make([]Point, 0, g.count)
+	g.ForEach(func(p Point) {
+		points = append(points, p)
+	})
+	sort.Slice(points, func(i, j int) bool {
+		a, b := points[i], points[j]
+		if a.Z != b.Z {
+			return a.Z < b.Z
+		}
+		if a.Y != b.Y {
+			return a.Y < b.Y
+		}
+		return a.X < b.X
+	})
+	return points
+}
+
+// The smallest box that contains every voxel that's set. Both corners are
+// inclusive. Returns false if the grid is empty.
+func (g *Grid) Bounds() (min, max Point, ok bool) {
+	g.ForEach(func(p Point) {
+		if !ok {
+			min, max, ok = p, p, true
+			return
+		}
+		min = Point{intMin(min.X, p.X), intMin(min.Y, p.Y), intMin(min.Z, p.Z)}
+		max = Point{intMax(max.X, p.X), intMax(max.Y, p.Y), intMax(max.Z, p.Z)}
+	})
+	return min, max, ok
+}
+
+func intMin(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func intMax(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,58 @@
+package voxel
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGridGetSet(t *testing.T) {
+	g := NewGrid()
+	points := []Point{
+		{0, 0, 0},
+		{-1, 0, 1},
+		{15, 16, -16},
+		{-17, -1, 3},
+		{1000000000, -1000000000, 5},
+	}
+	for _, p := range points {
+		if g.GetPoint(p) {
+			t.Errorf("Expected %v to start empty", p)
+		}
+		g.SetPoint(p, true)
+		// Setting twice shouldn't count twice.
+		g.SetPoint(p, true)
+	}
+	for _, p := range points {
+		if !g.GetPoint(p) {
+			t.Errorf("Expected %v to be set", p)
+		}
+		for _, direction := range FaceDirections {
+			if g.GetPoint(p.Add(direction)) {
+				t.Errorf("Expected %v next to %v to be empty", p.Add(direction), p)
+			}
+		}
+	}
+	if g.Len() != len(points) {
+		t.Errorf("Expected %d voxels, got %d", len(points), g.Len())
+	}
+
+	min, max, ok := g.Bounds()
+	if !ok || min != (Point{-17, -1000000000, -16}) || max != (Point{1000000000, 16, 5}) {
+		t.Errorf("Unexpected bounds %v to %v", min, max)
+	}
+
+	want := []Point{{15, 16, -16}, {0, 0, 0}, {-1, 0, 1}, {-17, -1, 3}, {1000000000, -1000000000, 5}}
+	if got := g.Points(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected points %v, got %v", want, got)
+	}
+
+	for _, p := range points {
+		g.SetPoint(p, false)
+	}
+	if g.Len() != 0 || len(g.chunks) != 0 {
+		t.Errorf("Expected the grid to be empty, got %d voxels in %d chunks", g.Len(), len(g.chunks))
+	}
+	if _, _, ok := g.Bounds(); ok {
+		t.Errorf("Expected an empty grid to have no bounds")
+	}
+}
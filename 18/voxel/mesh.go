@@ -0,0 +1,395 @@
+package voxel
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+)
+
+// A rectangle on the surface. The corners go anticlockwise when looking at
+// the rectangle from the side the normal points to.
+type Quad struct {
+	Corners [4]Point
+	Normal  Point
+	// The corners, plus any corners of other quads that are in the middle of
+	// one of this quad's edges, going around the same way. Nil if there aren't
+	// any. Triangles have to use these as well, or there'd be tiny gaps in the
+	// surface along those edges.
+	Outline []Point
+}
+
+// A surface made of rectangles. Each voxel covers the unit cube from its
+// coordinates to its coordinates plus one.
+type Mesh struct {
+	Quads []Quad
+}
+
+// Faces that point the same way, at the same depth along that axis. The
+// direction is an index into FaceDirections.
+type faceSlice struct {
+	direction int
+	depth     int
+}
+
+// Where a face is within a slice, along the two other axes.
+type faceCell struct {
+	u int
+	v int
+}
+
+// Creates a mesh of the surface of the voxels that can be reached from
+// outside. With greedy set, faces next to each other are merged into bigger
+// rectangles, which makes the mesh much smaller. Merged rectangles can meet in
+// the middle of the edge of another rectangle, so those edges are split up to
+// keep the surface closed.
+func ExteriorMesh(g *Grid, greedy bool) Mesh {
+	enclosed := EnclosedAir(g)
+
+	// Group the faces into slices that face the same way at the same depth,
+	// so each slice can be merged as a 2D grid.
+	slices := make(map[faceSlice][]faceCell)
+	g.ForEach(func(p Point) {
+		for direction, offset := range FaceDirections {
+			neighbour := p.Add(offset)
+			if g.GetPoint(neighbour) || enclosed.GetPoint(neighbour) {
+				continue
+			}
+			axis := direction / 2
+			coords := [3]int{p.X, p.Y, p.Z}
+			key := faceSlice{direction, coords[axis]}
+			slices[key] = append(slices[key], faceCell{coords[(axis+1)%3], coords[(axis+2)%3]})
+		}
+	})
+
+	// Go through the slices in order so the mesh comes out the same every
+	// time.
+	keys := make([]faceSlice, 0, len(slices))
+	for key := range slices {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].direction != keys[j].direction {
+			return keys[i].direction < keys[j].direction
+		}
+		return keys[i].depth < keys[j].depth
+	})
+
+	mesh := Mesh{}
+	for _, key := range keys {
+		cells := slices[key]
+		sort.Slice(cells, func(i, j int) bool {
+			if cells[i].v != cells[j].v {
+				return cells[i].v < cells[j].v
+			}
+			return cells[i].u < cells[j].u
+		})
+		if greedy {
+			for _, rect := range mergeCells(cells) {
+				mesh.Quads = append(mesh.Quads, newQuad(key, rect[0], rect[1]))
+			}
+		} else {
+			for _, cell := range cells {
+				mesh.Quads = append(mesh.Quads, newQuad(key, cell, faceCell{cell.u + 1, cell.v + 1}))
+			}
+		}
+	}
+	if greedy {
+		splitEdges(mesh.Quads)
+	}
+	return mesh
+}
+
+// A line along one of the axes, where the two other coordinates are a and b.
+type axisLine struct {
+	axis int
+	a    int
+	b    int
+}
+
+// Fills in the Outline of each quad that has corners of other quads in the
+// middle of its edges.
+func splitEdges(quads []Quad) {
+	// Where the corners are along each line they're on.
+	lines := make(map[axisLine][]int)
+	seen := make(map[Point]bool)
+	for _, q := range quads {
+		for _, corner := range q.Corners {
+			if seen[corner] {
+				continue
+			}
+			seen[corner] = true
+			coords := corner.coords()
+			for axis := 0; axis < 3; axis++ {
+				key := axisLine{axis, coords[(axis+1)%3], coords[(axis+2)%3]}
+				lines[key] = append(lines[key], coords[axis])
+			}
+		}
+	}
+	for _, positions := range lines {
+		sort.Ints(positions)
+	}
+
+	for i := range quads {
+		q := &quads[i]
+		outline := make([]Point, 0, 4)
+		for j, from := range q.Corners {
+			outline = append(outline, from)
+			to := q.Corners[(j+1)%4].coords()
+			start := from.coords()
+			// Only one coordinate changes along an edge.
+			axis := 0
+			for start[axis] == to[axis] {
+				axis++
+			}
+			positions := lines[axisLine{axis, start[(axis+1)%3], start[(axis+2)%3]}]
+			lo := sort.SearchInts(positions, intMin(start[axis], to[axis])+1)
+			hi := sort.SearchInts(positions, intMax(start[axis], to[axis]))
+			for k := lo; k < hi; k++ {
+				// Go along the edge in the same direction as the outline.
+				index := k
+				if start[axis] > to[axis] {
+					index = lo + hi - 1 - k
+				}
+				coords := start
+				coords[axis] = positions[index]
+				outline = append(outline, Point{coords[0], coords[1], coords[2]})
+			}
+		}
+		if len(outline) > 4 {
+			q.Outline = outline
+		}
+	}
+}
+
+func (p Point) coords() [3]int {
+	return [3]int{p.X, p.Y, p.Z}
+}
+
+// Merges cells into rectangles, returning the min (inclusive) and max
+// (exclusive) corners of each one. The cells must be sorted by v, then u.
+// Each rectangle is grown as far as it'll go along u, then along v.
+func mergeCells(cells []faceCell) [][2]faceCell {
+	remaining := make(map[faceCell]bool, len(cells))
+	for _, cell := range cells {
+		remaining[cell] = true
+	}
+
+	rects := make([][2]faceCell, 0)
+	for _, start := range cells {
+		if !remaining[start] {
+			continue
+		}
+		endU := start.u + 1
+		for remaining[faceCell{endU, start.v}] {
+			endU++
+		}
+		endV := start.v + 1
+		for rowRemaining(remaining, start.u, endU, endV) {
+			endV++
+		}
+		for v := start.v; v < endV; v++ {
+			for u := start.u; u < endU; u++ {
+				delete(remaining, faceCell{u, v})
+			}
+		}
+		rects = append(rects, [2]faceCell{start, {endU, endV}})
+	}
+	return rects
+}
+
+func rowRemaining(remaining map[faceCell]bool, fromU, toU, v int) bool {
+	for u := fromU; u < toU; u++ {
+		if !remaining[faceCell{u, v}] {
+			return false
+		}
+	}
+	return true
+}
+
+// Creates the quad for a rectangle of faces in a slice.
+func newQuad(slice faceSlice, min, max faceCell) Quad {
+	axis := slice.direction / 2
+	normal := FaceDirections[slice.direction]
+	// Faces on the positive side of a voxel are on the far side of its cube.
+	depth := slice.depth
+	if normal.X+normal.Y+normal.Z > 0 {
+		depth++
+	}
+
+	point := func(u, v int) Point {
+		var coords [3]int
+		coords[axis] = depth
+		coords[(axis+1)%3] = u
+		coords[(axis+2)%3] = v
+		return Point{coords[0], coords[1], coords[2]}
+	}
+	// u cross v points along the positive axis, so going around u then v is
+	// anticlockwise looking from that side.
+	q := Quad{
+		Corners: [4]Point{point(min.u, min.v), point(max.u, min.v), point(max.u, max.v), point(min.u, max.v)},
+		Normal:  normal,
+	}
+	if normal.X+normal.Y+normal.Z < 0 {
+		q.Corners[1], q.Corners[3] = q.Corners[3], q.Corners[1]
+	}
+	return q
+}
+
+// The total area of all the quads.
+func (m Mesh) Area() int {
+	area := 0
+	for _, q := range m.Quads {
+		// The sides are along the axes, so only one coordinate changes.
+		a := q.Corners[1].Sub(q.Corners[0])
+		b := q.Corners[3].Sub(q.Corners[0])
+		area += intAbs(a.X+a.Y+a.Z) * intAbs(b.X+b.Y+b.Z)
+	}
+	return area
+}
+
+// Splits the quad into triangles, which go anticlockwise the same way as the
+// quad.
+func (q Quad) Triangles() [][3]Point {
+	if q.Outline == nil {
+		return [][3]Point{
+			{q.Corners[0], q.Corners[1], q.Corners[2]},
+			{q.Corners[0], q.Corners[2], q.Corners[3]},
+		}
+	}
+	return triangulate(q.Outline)
+}
+
+// Splits a convex polygon into triangles, without making any flat triangles
+// from points that are in a line. It works by cutting off one corner at a
+// time.
+func triangulate(points []Point) [][3]Point {
+	points = append([]Point(nil), points...)
+	triangles := make([][3]Point, 0, len(points)-2)
+	for len(points) > 3 {
+		n := len(points)
+		for i, point := range points {
+			prev, next := points[(i+n-1)%n], points[(i+1)%n]
+			if inLine(prev, point, next) {
+				continue
+			}
+			// Don't leave the rest of the points in a line, or they'd only
+			// make flat triangles. There's always another corner we can cut.
+			flat := true
+			for _, other := range points {
+				if !inLine(prev, next, other) {
+					flat = other == point
+					if !flat {
+						break
+					}
+				}
+			}
+			if flat {
+				continue
+			}
+			triangles = append(triangles, [3]Point{prev, point, next})
+			points = append(points[:i], points[i+1:]...)
+			break
+		}
+	}
+	return append(triangles, [3]Point{points[0], points[1], points[2]})
+}
+
+func inLine(a, b, c Point) bool {
+	u, v := b.Sub(a), c.Sub(a)
+	return u.Y*v.Z == u.Z*v.Y && u.Z*v.X == u.X*v.Z && u.X*v.Y == u.Y*v.X
+}
+
+// How many triangles the quads are split into.
+func (m Mesh) NumTriangles() int {
+	count := 0
+	for _, q := range m.Quads {
+		if q.Outline == nil {
+			count += 2
+		} else {
+			// Cutting off a corner removes one point and makes one triangle,
+			// and the last three points make the last triangle.
+			count += len(q.Outline) - 2
+		}
+	}
+	return count
+}
+
+// Writes the mesh as a Wavefront OBJ file, made of triangles.
+func (m Mesh) WriteOBJ(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintln(bw, "# Exterior surface of the voxels")
+
+	// Corners are shared between quads, so only write each one once.
+	vertexIDs := make(map[Point]int)
+	for _, q := range m.Quads {
+		corners := q.Corners[:]
+		if q.Outline != nil {
+			corners = q.Outline
+		}
+		for _, corner := range corners {
+			if _, ok := vertexIDs[corner]; !ok {
+				// OBJ indices start at 1.
+				vertexIDs[corner] = len(vertexIDs) + 1
+				fmt.Fprintf(bw, "v %d %d %d\n", corner.X, corner.Y, corner.Z)
+			}
+		}
+	}
+	for _, normal := range FaceDirections {
+		fmt.Fprintf(bw, "vn %d %d %d\n", normal.X, normal.Y, normal.Z)
+	}
+	for _, q := range m.Quads {
+		normalID := directionIndex(q.Normal) + 1
+		for _, tri := range q.Triangles() {
+			fmt.Fprintf(bw, "f %d//%d %d//%d %d//%d\n",
+				vertexIDs[tri[0]], normalID, vertexIDs[tri[1]], normalID, vertexIDs[tri[2]], normalID)
+		}
+	}
+	return bw.Flush()
+}
+
+// Writes the mesh as a binary STL file, made of triangles.
+func (m Mesh) WriteSTL(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	var header [80]byte
+	copy(header[:], "Exterior surface of the voxels")
+	bw.Write(header[:])
+
+	numTriangles := m.NumTriangles()
+	if uint64(numTriangles) > math.MaxUint32 {
+		return fmt.Errorf("too many triangles for an STL file: %d", numTriangles)
+	}
+	binary.Write(bw, binary.LittleEndian, uint32(numTriangles))
+
+	for _, q := range m.Quads {
+		for _, tri := range q.Triangles() {
+			values := make([]float32, 0, 12)
+			values = append(values, float32(q.Normal.X), float32(q.Normal.Y), float32(q.Normal.Z))
+			for _, p := range tri {
+				values = append(values, float32(p.X), float32(p.Y), float32(p.Z))
+			}
+			binary.Write(bw, binary.LittleEndian, values)
+			// Attribute byte count, which is unused.
+			binary.Write(bw, binary.LittleEndian, uint16(0))
+		}
+	}
+	return bw.Flush()
+}
+
+func directionIndex(direction Point) int {
+	for i, d := range FaceDirections {
+		if d == direction {
+			return i
+		}
+	}
+	panic(fmt.Sprintf("Not a face direction: %v", direction))
+}
+
+func intAbs(a int) int {
+	if a < 0 {
+		return -a
+	}
+	return a
+}
@@ -0,0 +1,201 @@
+package voxel
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"strings"
+	"testing"
+)
+
+func cross(a, b Point) Point {
+	return Point{a.Y*b.Z - a.Z*b.Y, a.Z*b.X - a.X*b.Z, a.X*b.Y - a.Y*b.X}
+}
+
+// Every quad should wind anticlockwise around its normal.
+func checkWinding(t *testing.T, m Mesh) {
+	t.Helper()
+	for _, q := range m.Quads {
+		n := cross(q.Corners[1].Sub(q.Corners[0]), q.Corners[3].Sub(q.Corners[0]))
+		dot := n.X*q.Normal.X + n.Y*q.Normal.Y + n.Z*q.Normal.Z
+		if dot <= 0 || n != (Point{dot * q.Normal.X, dot * q.Normal.Y, dot * q.Normal.Z}) {
+			t.Fatalf("Quad %v winds the wrong way for its normal", q)
+		}
+	}
+}
+
+func TestExteriorMeshExample(t *testing.T) {
+	g := exampleGrid(t, Point{-3, 10, 0})
+	faces := ExteriorMesh(g, false)
+	merged := ExteriorMesh(g, true)
+	if len(faces.Quads) != 58 || faces.Area() != 58 {
+		t.Errorf("Expected 58 unit faces, got %d quads with area %d", len(faces.Quads), faces.Area())
+	}
+	if merged.Area() != 58 || len(merged.Quads) >= 58 {
+		t.Errorf("Expected fewer quads with the same area once merged, got %d quads with area %d", len(merged.Quads), merged.Area())
+	}
+	checkWinding(t, faces)
+	checkWinding(t, merged)
+}
+
+func TestExteriorMeshBlock(t *testing.T) {
+	g := NewGrid()
+	for z := 0; z < 2; z++ {
+		for y := -3; y < 0; y++ {
+			for x := 0; x < 4; x++ {
+				g.Set(x, y, z, true)
+			}
+		}
+	}
+
+	merged := ExteriorMesh(g, true)
+	if len(merged.Quads) != 6 || merged.Area() != 2*(4*3+4*2+3*2) {
+		t.Errorf("Expected each side of the block to be one quad, got %d quads", len(merged.Quads))
+	}
+	checkWinding(t, merged)
+
+	// Without merging, the surface should be closed: every edge is shared by
+	// two faces, which go along it in opposite directions.
+	edges := make(map[[2]Point]int)
+	for _, q := range ExteriorMesh(g, false).Quads {
+		for i := range q.Corners {
+			edges[[2]Point{q.Corners[i], q.Corners[(i+1)%4]}]++
+		}
+	}
+	for edge, count := range edges {
+		if count != 1 || edges[[2]Point{edge[1], edge[0]}] != 1 {
+			t.Fatalf("Edge %v is used %d times, and %d times backwards", edge, count, edges[[2]Point{edge[1], edge[0]}])
+		}
+	}
+}
+
+// The triangles should make a closed surface: every edge is used as many times
+// going one way as going the other way. Edges that only touch diagonally can
+// be shared by four triangles.
+func checkClosed(t *testing.T, m Mesh) {
+	t.Helper()
+	edges := make(map[[2]Point]int)
+	for _, q := range m.Quads {
+		for _, tri := range q.Triangles() {
+			n := cross(tri[1].Sub(tri[0]), tri[2].Sub(tri[0]))
+			if n.X*q.Normal.X+n.Y*q.Normal.Y+n.Z*q.Normal.Z <= 0 {
+				t.Fatalf("Triangle %v of quad %v is flat or winds the wrong way", tri, q)
+			}
+			for i := range tri {
+				edges[[2]Point{tri[i], tri[(i+1)%3]}]++
+			}
+		}
+	}
+	for edge, count := range edges {
+		if backwards := edges[[2]Point{edge[1], edge[0]}]; backwards != count {
+			t.Fatalf("Edge %v is used %d times, but %d times backwards", edge, count, backwards)
+		}
+	}
+}
+
+// Merging leaves the corners of some quads in the middle of the edges of
+// others, which have to be split up so the surface stays closed.
+func TestExteriorMeshClosed(t *testing.T) {
+	stairs := NewGrid()
+	for x := 0; x < 4; x++ {
+		for z := 0; z <= x; z++ {
+			stairs.Set(x, 0, z, true)
+			stairs.Set(x, 1, z, x%2 == 0)
+		}
+	}
+	cases := []struct {
+		name  string
+		grid  *Grid
+		split bool
+	}{
+		{"example", exampleGrid(t, Point{-3, 10, 0}), false},
+		{"stairs", stairs, true},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			g := c.grid
+			merged := ExteriorMesh(g, true)
+			split := false
+			for _, q := range merged.Quads {
+				split = split || q.Outline != nil
+			}
+			if split != c.split {
+				t.Errorf("Expected split edges to be %v, got %v", c.split, split)
+			}
+			triangles := 0
+			for _, q := range merged.Quads {
+				triangles += len(q.Triangles())
+			}
+			if triangles != merged.NumTriangles() {
+				t.Errorf("Expected %d triangles, got %d", merged.NumTriangles(), triangles)
+			}
+			checkClosed(t, merged)
+			checkClosed(t, ExteriorMesh(g, false))
+		})
+	}
+}
+
+func TestTriangulate(t *testing.T) {
+	// A 3 by 2 rectangle with extra points along the bottom and the right.
+	points := []Point{{0, 0, 0}, {1, 0, 0}, {2, 0, 0}, {3, 0, 0}, {3, 1, 0}, {3, 2, 0}, {0, 2, 0}}
+	triangles := triangulate(points)
+	if len(triangles) != len(points)-2 {
+		t.Fatalf("Expected %d triangles, got %d", len(points)-2, len(triangles))
+	}
+	area := 0
+	for _, tri := range triangles {
+		n := cross(tri[1].Sub(tri[0]), tri[2].Sub(tri[0]))
+		if n.Z <= 0 {
+			t.Fatalf("Triangle %v is flat or winds the wrong way", tri)
+		}
+		area += n.Z
+	}
+	if area != 2*3*2 {
+		t.Errorf("Expected the triangles to cover the rectangle, got twice the area as %d", area)
+	}
+}
+
+func TestWriteMesh(t *testing.T) {
+	g := NewGrid()
+	g.Set(5, -5, 0, true)
+	m := ExteriorMesh(g, true)
+
+	var obj bytes.Buffer
+	if err := m.WriteOBJ(&obj); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(obj.String(), "\n")
+	counts := make(map[string]int)
+	for _, line := range lines {
+		if fields := strings.Fields(line); len(fields) > 0 {
+			counts[fields[0]]++
+		}
+	}
+	if counts["v"] != 8 || counts["vn"] != 6 || counts["f"] != 12 {
+		t.Errorf("Expected 8 vertices, 6 normals and 12 triangles, got %v", counts)
+	}
+	if !strings.Contains(obj.String(), "v 6 -4 1\n") {
+		t.Errorf("Expected the far corner of the cube in\n%s", obj.String())
+	}
+
+	var stl bytes.Buffer
+	if err := m.WriteSTL(&stl); err != nil {
+		t.Fatal(err)
+	}
+	data := stl.Bytes()
+	if len(data) != 84+12*50 {
+		t.Fatalf("Expected %d bytes, got %d", 84+12*50, len(data))
+	}
+	if n := binary.LittleEndian.Uint32(data[80:]); n != 12 {
+		t.Errorf("Expected 12 triangles, got %d", n)
+	}
+	// The first triangle is from the +x face, so its normal is (1, 0, 0) and
+	// its first vertex is on x = 6.
+	floats := make([]float32, 6)
+	for i := range floats {
+		floats[i] = math.Float32frombits(binary.LittleEndian.Uint32(data[84+4*i:]))
+	}
+	if floats[0] != 1 || floats[1] != 0 || floats[2] != 0 || floats[3] != 6 {
+		t.Errorf("Unexpected first triangle %v", floats)
+	}
+}
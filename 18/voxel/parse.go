@@ -0,0 +1,66 @@
+package voxel
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Reads x,y,z coordinates, one per line. Coordinates can be negative.
+func ParsePoints(r io.Reader) ([]Point, error) {
+	points := make([]Point, 0)
+	scanner := bufio.NewScanner(r)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		// Split the line into a slice of strings
+		lineParts := strings.Split(line, ",")
+		if len(lineParts) != 3 {
+			return nil, fmt.Errorf("line %d: expected 3 coordinates, got %q", lineNum, line)
+		}
+
+		// Convert the strings to ints
+		var coords [3]int
+		for i, part := range lineParts {
+			coord, err := strconv.Atoi(strings.TrimSpace(part))
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", lineNum, err)
+			}
+			coords[i] = coord
+		}
+		points = append(points, Point{coords[0], coords[1], coords[2]})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return points, nil
+}
+
+func ReadPoints(filename string) ([]Point, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	points, err := ParsePoints(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", filename, err)
+	}
+	return points, nil
+}
+
+func GridFromPoints(points []Point) *Grid {
+	g := NewGrid()
+	for _, point := range points {
+		g.SetPoint(point, true)
+	}
+	return g
+}
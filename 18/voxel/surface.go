@@ -0,0 +1,180 @@
+package voxel
+
+// The directions to each of the 26 voxels around a voxel, including ones that
+// only touch at an edge or a corner.
+var neighbourDirections = func() []Point {
+	directions := make([]Point, 0, 26)
+	for z := -1; z <= 1; z++ {
+		for y := -1; y <= 1; y++ {
+			for x := -1; x <= 1; x++ {
+				if x != 0 || y != 0 || z != 0 {
+					directions = append(directions, Point{x, y, z})
+				}
+			}
+		}
+	}
+	return directions
+}()
+
+// Counts the faces of the voxels that aren't touching another voxel.
+func SurfaceArea(g *Grid) int {
+	surfaceArea := 0
+	g.ForEach(func(p Point) {
+		for _, direction := range FaceDirections {
+			if !g.GetPoint(p.Add(direction)) {
+				surfaceArea++
+			}
+		}
+	})
+	return surfaceArea
+}
+
+// Counts the faces of the voxels that can be reached from outside, so not
+// counting the faces around any trapped pockets of air.
+func ExteriorSurfaceArea(g *Grid) int {
+	enclosed := EnclosedAir(g)
+	surfaceArea := 0
+	g.ForEach(func(p Point) {
+		for _, direction := range FaceDirections {
+			neighbour := p.Add(direction)
+			if !g.GetPoint(neighbour) && !enclosed.GetPoint(neighbour) {
+				surfaceArea++
+			}
+		}
+	})
+	return surfaceArea
+}
+
+// Finds all the empty voxels that are completely surrounded, so they can't be
+// reached from outside by moving between empty voxels that share a face.
+//
+// Doing a flood fill over the bounding box of the whole grid would be too slow
+// if the voxels are spread out, so instead each cluster of voxels is filled
+// separately. Air can't get through gaps where voxels only touch at an edge or
+// corner, so any pocket of air is enclosed by a single cluster of voxels that
+// touch at a face, edge or corner.
+//
+// Even a single cluster can have a bounding box that's mostly empty, like a
+// long diagonal line, so the outside is only filled in the shell of air right
+// next to the cluster. The air next to a cluster that's connected to the
+// outside is all connected within that shell, so anything in the shell that
+// the fill doesn't reach is the edge of a pocket.
+func EnclosedAir(g *Grid) *Grid {
+	enclosed := NewGrid()
+	for _, cluster := range clusters(g) {
+		shell := airShell(cluster)
+		// Nothing in the cluster is lower than its first point, so the air
+		// under it is outside.
+		start := cluster.Points()[0].Add(Point{0, 0, -1})
+		outside := floodFill(start, shell.GetPoint)
+
+		min, max, _ := cluster.Bounds()
+		min = min.Add(Point{-1, -1, -1})
+		max = max.Add(Point{1, 1, 1})
+		checked := NewGrid()
+		for _, p := range shell.Points() {
+			if outside.GetPoint(p) || checked.GetPoint(p) {
+				continue
+			}
+			// The pocket is surrounded by the cluster, so this fill stays
+			// inside it. The padded box is only there in case that's ever not
+			// true, so the fill would get round to the start and stop.
+			pocket := floodFill(p, func(q Point) bool {
+				return inBox(q, min, max) && !cluster.GetPoint(q)
+			})
+			if pocket.GetPoint(start) {
+				continue
+			}
+			pocket.ForEach(func(q Point) {
+				checked.SetPoint(q, true)
+				// This might be a voxel from a different cluster inside this
+				// one, which isn't air.
+				if !g.GetPoint(q) {
+					enclosed.SetPoint(q, true)
+				}
+			})
+		}
+	}
+	return enclosed
+}
+
+// The empty voxels that touch one of the voxels at a face, edge or corner.
+func airShell(g *Grid) *Grid {
+	shell := NewGrid()
+	g.ForEach(func(p Point) {
+		for _, direction := range neighbourDirections {
+			neighbour := p.Add(direction)
+			if !g.GetPoint(neighbour) {
+				shell.SetPoint(neighbour, true)
+			}
+		}
+	})
+	return shell
+}
+
+// Flood fills from start, moving between voxels that share a face, as long as
+// open returns true for them. Returns the voxels that were filled, including
+// start.
+func floodFill(start Point, open func(p Point) bool) *Grid {
+	filled := NewGrid()
+	filled.SetPoint(start, true)
+	toBeExplored := []Point{start}
+	for len(toBeExplored) > 0 {
+		next := toBeExplored[len(toBeExplored)-1]
+		toBeExplored = toBeExplored[:len(toBeExplored)-1]
+
+		for _, direction := range FaceDirections {
+			neighbour := next.Add(direction)
+			if filled.GetPoint(neighbour) || !open(neighbour) {
+				continue
+			}
+			filled.SetPoint(neighbour, true)
+			toBeExplored = append(toBeExplored, neighbour)
+		}
+	}
+	return filled
+}
+
+// Splits the voxels into groups that touch each other at a face, edge or
+// corner.
+func clusters(g *Grid) []*Grid {
+	return components(g, neighbourDirections)
+}
+
+// Splits the voxels into groups that are connected by moving in the given
+// directions. The groups are in order of their first voxel in Points.
+func components(g *Grid, directions []Point) []*Grid {
+	seen := NewGrid()
+	groups := make([]*Grid, 0)
+	for _, start := range g.Points() {
+		if seen.GetPoint(start) {
+			continue
+		}
+		group := NewGrid()
+		seen.SetPoint(start, true)
+		group.SetPoint(start, true)
+		toBeExplored := []Point{start}
+		for len(toBeExplored) > 0 {
+			next := toBeExplored[len(toBeExplored)-1]
+			toBeExplored = toBeExplored[:len(toBeExplored)-1]
+
+			for _, direction := range directions {
+				neighbour := next.Add(direction)
+				if !g.GetPoint(neighbour) || seen.GetPoint(neighbour) {
+					continue
+				}
+				seen.SetPoint(neighbour, true)
+				group.SetPoint(neighbour, true)
+				toBeExplored = append(toBeExplored, neighbour)
+			}
+		}
+		groups = append(groups, group)
+	}
+	return groups
+}
+
+func inBox(p, min, max Point) bool {
+	return p.X >= min.X && p.X <= max.X &&
+		p.Y >= min.Y && p.Y <= max.Y &&
+		p.Z >= min.Z && p.Z <= max.Z
+}
@@ -0,0 +1,115 @@
+package voxel
+
+import (
+	"strings"
+	"testing"
+)
+
+const exampleInput = `2,2,2
+1,2,2
+3,2,2
+2,1,2
+2,3,2
+2,2,1
+2,2,3
+2,2,4
+2,2,6
+1,2,5
+3,2,5
+2,1,5
+2,3,5
+`
+
+func exampleGrid(t testing.TB, offset Point) *Grid {
+	points, err := ParsePoints(strings.NewReader(exampleInput))
+	if err != nil {
+		t.Fatal(err)
+	}
+	g := NewGrid()
+	for _, p := range points {
+		g.SetPoint(p.Add(offset), true)
+	}
+	return g
+}
+
+func TestSurfaceAreaExample(t *testing.T) {
+	for _, offset := range []Point{{0, 0, 0}, {-100, -7, -3}, {-1000000000, 1000000000, 5}} {
+		g := exampleGrid(t, offset)
+		if area := SurfaceArea(g); area != 64 {
+			t.Errorf("Offset %v: expected surface area 64, got %d", offset, area)
+		}
+		if area := ExteriorSurfaceArea(g); area != 58 {
+			t.Errorf("Offset %v: expected exterior surface area 58, got %d", offset, area)
+		}
+	}
+}
+
+// Two copies of the example a long way apart shouldn't need a flood fill over
+// all the space between them.
+func TestSurfaceAreaSparse(t *testing.T) {
+	g := exampleGrid(t, Point{})
+	exampleGrid(t, Point{1 << 40, -(1 << 40), 1 << 40}).ForEach(func(p Point) {
+		g.SetPoint(p, true)
+	})
+	if area := ExteriorSurfaceArea(g); area != 2*58 {
+		t.Errorf("Expected exterior surface area %d, got %d", 2*58, area)
+	}
+}
+
+// A long diagonal line is one cluster, but its bounding box is nearly all
+// empty, so filling the whole box would be far too slow.
+func TestSurfaceAreaDiagonal(t *testing.T) {
+	g := NewGrid()
+	for i := 0; i < 1000; i++ {
+		g.Set(i, i, i, true)
+	}
+	if area := ExteriorSurfaceArea(g); area != 6*1000 {
+		t.Errorf("Expected exterior surface area %d, got %d", 6*1000, area)
+	}
+}
+
+// A hollow cube, with another voxel floating inside it.
+func TestSurfaceAreaNested(t *testing.T) {
+	g := NewGrid()
+	for z := 0; z < 5; z++ {
+		for y := 0; y < 5; y++ {
+			for x := 0; x < 5; x++ {
+				if x == 0 || x == 4 || y == 0 || y == 4 || z == 0 || z == 4 {
+					g.Set(x, y, z, true)
+				}
+			}
+		}
+	}
+	g.Set(2, 2, 2, true)
+
+	if area := SurfaceArea(g); area != 6*25+6*9+6 {
+		t.Errorf("Expected surface area %d, got %d", 6*25+6*9+6, area)
+	}
+	if area := ExteriorSurfaceArea(g); area != 6*25 {
+		t.Errorf("Expected exterior surface area %d, got %d", 6*25, area)
+	}
+	if enclosed := EnclosedAir(g); enclosed.Len() != 3*3*3-1 || enclosed.Get(2, 2, 2) {
+		t.Errorf("Expected the inside of the cube apart from the middle to be enclosed, got %d voxels", enclosed.Len())
+	}
+}
+
+func TestParsePointsErrors(t *testing.T) {
+	tests := []struct {
+		input string
+		err   string
+	}{
+		{"1,2,3\n1,2\n", "line 2: expected 3 coordinates"},
+		{"1,2,x\n", "line 1:"},
+	}
+	for _, test := range tests {
+		_, err := ParsePoints(strings.NewReader(test.input))
+		if err == nil || !strings.Contains(err.Error(), test.err) {
+			t.Errorf("ParsePoints(%q): expected error containing %q, got %v", test.input, test.err, err)
+		}
+	}
+
+	points, err := ParsePoints(strings.NewReader(" -1, 2,-3 \n\n"))
+	if err != nil || len(points) != 1 || points[0] != (Point{-1, 2, -3}) {
+		t.Errorf("Expected a single point with negative coordinates, got %v, %v", points, err)
+	}
+}
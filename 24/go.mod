@@ -1,3 +1,7 @@
 module avent2022/24
 
 go 1.19
+
+require advent2022/common v0.0.0
+
+replace advent2022/common => ../common
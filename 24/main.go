@@ -1,10 +1,11 @@
 package main
 
 import (
-	"container/heap"
 	"fmt"
 	"os"
 	"time"
+
+	"advent2022/common/pq"
 )
 
 type Point struct {
@@ -25,36 +26,6 @@ type Position struct {
 	Prev *Position
 }
 
-type PositionsByDistanceToEnd struct {
-	Positions []Position
-	End       Point
-}
-
-func (p PositionsByDistanceToEnd) Len() int {
-	return len(p.Positions)
-}
-
-func (p PositionsByDistanceToEnd) Less(i, j int) bool {
-	return p.Positions[i].AStarValue(p.End) < p.Positions[j].AStarValue(p.End)
-}
-
-func (p PositionsByDistanceToEnd) Swap(i, j int) {
-	p.Positions[i], p.Positions[j] = p.Positions[j], p.Positions[i]
-}
-
-func (p *PositionsByDistanceToEnd) Push(x interface{}) {
-	item := x.(Position)
-	p.Positions = append(p.Positions, item)
-}
-
-func (p *PositionsByDistanceToEnd) Pop() interface{} {
-	old := p.Positions
-	n := len(old)
-	item := old[n-1]
-	p.Positions = old[0 : n-1]
-	return item
-}
-
 // A delta for moving each direction, and one for staying still.
 var deltas = []Position{
 	{P: Point{X: 0, Y: -1}, T: 1},
@@ -86,11 +57,14 @@ func search(b *Blizzard, start Position, end Point) Position {
 	// For debugging
 	// visitedPoints := make(map[Point]bool)
 
-	toVisit := PositionsByDistanceToEnd{Positions: []Position{start}, End: end}
+	toVisit := pq.New(func(a, b Position) bool {
+		return a.AStarValue(end) < b.AStarValue(end)
+	})
+	toVisit.Push(start)
 	i := 0
 	for toVisit.Len() > 0 {
 		// Pop the first element
-		p := heap.Pop(&toVisit).(Position)
+		p := toVisit.Pop()
 
 		// Check if this is a valid position
 		if !b.IsEmptyAtTime(p.P.X, p.P.Y, p.T) {
@@ -126,7 +100,7 @@ func search(b *Blizzard, start Position, end Point) Position {
 				T:    p.T + delta.T,
 				Prev: &p,
 			}
-			heap.Push(&toVisit, newPosition)
+			toVisit.Push(newPosition)
 		}
 	}
 	return Position{T: -1}
@@ -0,0 +1,3 @@
+module advent2022/common
+
+go 1.19
@@ -0,0 +1,167 @@
+// Package pq has generic priority queues, for searches like Dijkstra and A*.
+package pq
+
+// A priority queue that always pops the smallest item, according to less.
+type PriorityQueue[T any] struct {
+	items []T
+	less  func(a, b T) bool
+}
+
+func New[T any](less func(a, b T) bool) *PriorityQueue[T] {
+	return &PriorityQueue[T]{less: less}
+}
+
+func (pq *PriorityQueue[T]) Len() int {
+	return len(pq.items)
+}
+
+func (pq *PriorityQueue[T]) Push(item T) {
+	pq.items = append(pq.items, item)
+	up(len(pq.items)-1, pq.lessAt, pq.swap)
+}
+
+// Removes and returns the smallest item. Panics if the queue is empty.
+func (pq *PriorityQueue[T]) Pop() T {
+	last := len(pq.items) - 1
+	pq.swap(0, last)
+	item := pq.items[last]
+	pq.items = pq.items[:last]
+	down(0, len(pq.items), pq.lessAt, pq.swap)
+	return item
+}
+
+// Returns the smallest item without removing it. Panics if the queue is empty.
+func (pq *PriorityQueue[T]) Peek() T {
+	return pq.items[0]
+}
+
+func (pq *PriorityQueue[T]) lessAt(i, j int) bool {
+	return pq.less(pq.items[i], pq.items[j])
+}
+
+func (pq *PriorityQueue[T]) swap(i, j int) {
+	pq.items[i], pq.items[j] = pq.items[j], pq.items[i]
+}
+
+// A priority queue where each item has a key, and there's at most one item for
+// each key. This means an item's priority can be changed while it's in the
+// queue, e.g. to decrease a node's distance in Dijkstra's algorithm.
+type IndexedPriorityQueue[K comparable, T any] struct {
+	keys  []K
+	items []T
+	// Where each key is in the heap.
+	index map[K]int
+	less  func(a, b T) bool
+}
+
+func NewIndexed[K comparable, T any](less func(a, b T) bool) *IndexedPriorityQueue[K, T] {
+	return &IndexedPriorityQueue[K, T]{
+		index: make(map[K]int),
+		less:  less,
+	}
+}
+
+func (pq *IndexedPriorityQueue[K, T]) Len() int {
+	return len(pq.items)
+}
+
+func (pq *IndexedPriorityQueue[K, T]) Contains(key K) bool {
+	_, ok := pq.index[key]
+	return ok
+}
+
+// Gets the item for a key, if it's in the queue.
+func (pq *IndexedPriorityQueue[K, T]) Get(key K) (T, bool) {
+	i, ok := pq.index[key]
+	if !ok {
+		var zero T
+		return zero, false
+	}
+	return pq.items[i], true
+}
+
+// Adds an item for a key, replacing the item that's already there for the key
+// if there is one.
+func (pq *IndexedPriorityQueue[K, T]) Push(key K, item T) {
+	if i, ok := pq.index[key]; ok {
+		pq.items[i] = item
+		// The item could have moved either way.
+		if !up(i, pq.lessAt, pq.swap) {
+			down(i, len(pq.items), pq.lessAt, pq.swap)
+		}
+		return
+	}
+	pq.keys = append(pq.keys, key)
+	pq.items = append(pq.items, item)
+	pq.index[key] = len(pq.items) - 1
+	up(len(pq.items)-1, pq.lessAt, pq.swap)
+}
+
+// Replaces the item for a key if the new item is smaller, or adds it if the
+// key isn't in the queue. Returns whether the item was used.
+func (pq *IndexedPriorityQueue[K, T]) DecreaseKey(key K, item T) bool {
+	if existing, ok := pq.Get(key); ok && !pq.less(item, existing) {
+		return false
+	}
+	pq.Push(key, item)
+	return true
+}
+
+// Removes and returns the smallest item and its key. Panics if the queue is
+// empty.
+func (pq *IndexedPriorityQueue[K, T]) Pop() (K, T) {
+	last := len(pq.items) - 1
+	pq.swap(0, last)
+	key, item := pq.keys[last], pq.items[last]
+	pq.keys = pq.keys[:last]
+	pq.items = pq.items[:last]
+	delete(pq.index, key)
+	down(0, len(pq.items), pq.lessAt, pq.swap)
+	return key, item
+}
+
+func (pq *IndexedPriorityQueue[K, T]) lessAt(i, j int) bool {
+	return pq.less(pq.items[i], pq.items[j])
+}
+
+func (pq *IndexedPriorityQueue[K, T]) swap(i, j int) {
+	pq.keys[i], pq.keys[j] = pq.keys[j], pq.keys[i]
+	pq.items[i], pq.items[j] = pq.items[j], pq.items[i]
+	pq.index[pq.keys[i]] = i
+	pq.index[pq.keys[j]] = j
+}
+
+// Moves the item at i up the heap until its parent isn't bigger than it.
+// Returns whether it moved.
+func up(i int, less func(i, j int) bool, swap func(i, j int)) bool {
+	start := i
+	for i > 0 {
+		parent := (i - 1) / 2
+		if !less(i, parent) {
+			break
+		}
+		swap(i, parent)
+		i = parent
+	}
+	return i != start
+}
+
+// Moves the item at i down the heap until neither of its children are smaller
+// than it.
+func down(i int, n int, less func(i, j int) bool, swap func(i, j int)) {
+	for {
+		smallest := i
+		left, right := 2*i+1, 2*i+2
+		if left < n && less(left, smallest) {
+			smallest = left
+		}
+		if right < n && less(right, smallest) {
+			smallest = right
+		}
+		if smallest == i {
+			return
+		}
+		swap(i, smallest)
+		i = smallest
+	}
+}
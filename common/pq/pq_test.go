@@ -0,0 +1,72 @@
+package pq
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+func intLess(a, b int) bool {
+	return a < b
+}
+
+func TestPriorityQueue(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	items := make([]int, 100)
+	q := New(intLess)
+	for i := range items {
+		items[i] = r.Intn(50)
+		q.Push(items[i])
+	}
+	sort.Ints(items)
+
+	if q.Peek() != items[0] {
+		t.Errorf("Expected to peek %d, got %d", items[0], q.Peek())
+	}
+	for i, expected := range items {
+		if q.Len() != len(items)-i {
+			t.Fatalf("Expected length %d, got %d", len(items)-i, q.Len())
+		}
+		if got := q.Pop(); got != expected {
+			t.Errorf("Pop %d: expected %d, got %d", i, expected, got)
+		}
+	}
+}
+
+func TestIndexedPriorityQueue(t *testing.T) {
+	q := NewIndexed[string](intLess)
+	q.Push("a", 5)
+	q.Push("b", 3)
+	q.Push("c", 8)
+	q.Push("d", 1)
+
+	// Move c to the front, and d to the back.
+	if !q.DecreaseKey("c", 0) {
+		t.Errorf("Expected decreasing c to 0 to succeed")
+	}
+	if q.DecreaseKey("b", 4) {
+		t.Errorf("Expected decreasing b to 4 to fail, since it's already 3")
+	}
+	q.Push("d", 10)
+
+	if !q.Contains("a") || q.Contains("e") {
+		t.Errorf("Expected to contain a and not e")
+	}
+	if item, ok := q.Get("b"); !ok || item != 3 {
+		t.Errorf("Expected b to be 3, got %d, %v", item, ok)
+	}
+
+	expected := []struct {
+		key  string
+		item int
+	}{{"c", 0}, {"b", 3}, {"a", 5}, {"d", 10}}
+	for _, e := range expected {
+		key, item := q.Pop()
+		if key != e.key || item != e.item {
+			t.Errorf("Expected %s=%d, got %s=%d", e.key, e.item, key, item)
+		}
+	}
+	if q.Len() != 0 || q.Contains("a") {
+		t.Errorf("Expected the queue to be empty")
+	}
+}